@@ -0,0 +1,59 @@
+package swagger
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+
+	"github.com/gofiber/fiber/v2/log"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// Files of the Swagger UI distribution the page and the OAuth2 flows depend on
+var requiredAssets = []string{
+	"swagger-ui-bundle.js", "swagger-ui-standalone-preset.js", "swagger-ui.css", "oauth2-redirect.html",
+}
+
+// overlayFS serves the files of fsys, and the ones it lacks from fallback
+type overlayFS struct {
+	fsys     fs.FS
+	fallback fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	f, err := o.fsys.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return o.fallback.Open(name)
+	}
+	return f, err
+}
+
+// assetFS returns the file system the static assets are served from, assets layered on top of the embedded bundle
+func assetFS(assets fs.FS) (fs.FS, error) {
+	if assets == nil {
+		return swaggerFiles.FS, nil
+	}
+
+	var missing []string
+	for _, name := range requiredAssets {
+		if _, err := fs.Stat(assets, name); err != nil {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == len(requiredAssets) {
+		// Most likely the root of the distribution is a subdirectory, see fs.Sub
+		return nil, errors.New("fiber: swagger middleware error -> Assets contains none of the Swagger UI files, e.g. swagger-ui-bundle.js")
+	}
+	if len(missing) > 0 {
+		log.Warnw("swagger: files missing from Assets are served from the embedded bundle", "files", strings.Join(missing, ", "))
+	}
+	return overlayFS{fsys: assets, fallback: swaggerFiles.FS}, nil
+}
+
+// assetURL returns the url the page loads the asset name from, relative to the page unless base is set
+func assetURL(base, name string) string {
+	if base == "" {
+		return "./" + name
+	}
+	return strings.TrimSuffix(base, "/") + "/" + name
+}
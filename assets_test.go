@@ -0,0 +1,127 @@
+package swagger
+
+import (
+	"io"
+	"io/fs"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gofiber/fiber/v2"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+func Test_Assets(t *testing.T) {
+	dist := fstest.MapFS{
+		"swagger-ui-bundle.js":            {Data: []byte("// bundle 5.17.14")},
+		"swagger-ui-standalone-preset.js": {Data: []byte("// preset 5.17.14")},
+		"swagger-ui.css":                  {Data: []byte("/* css 5.17.14 */")},
+	}
+	favicon, err := fs.ReadFile(swaggerFiles.FS, "favicon-16x16.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		compress   bool
+		url        string
+		statusCode int
+		body       string
+	}{
+		{name: "Should serve the custom bundle", url: "/swag/swagger-ui-bundle.js", statusCode: 200, body: "// bundle 5.17.14"},
+		{name: "Should serve the custom bundle from memory", compress: true, url: "/swag/swagger-ui.css", statusCode: 200, body: "/* css 5.17.14 */"},
+		{name: "Should fall back to the embedded bundle", url: "/swag/favicon-16x16.png", statusCode: 200, body: string(favicon)},
+		{name: "Should fall back for required files", url: "/swag/oauth2-redirect.html", statusCode: 200},
+		{name: "Should return status 404 for unknown files", url: "/swag/unknown.js", statusCode: 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/swag/*", New(Config{Spec: BytesSpec([]byte(`{"swagger":"2.0"}`)), Assets: dist, Compress: tt.compress}))
+
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.statusCode {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, tt.statusCode)
+			}
+			if tt.body == "" {
+				return
+			}
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(body) != tt.body {
+				t.Fatalf("Body: got %s - expected %s", body, tt.body)
+			}
+		})
+	}
+
+	t.Run("Should reject a file system without Swagger UI files", func(t *testing.T) {
+		_, err := NewE(Config{
+			Spec:   BytesSpec([]byte(`{"swagger":"2.0"}`)),
+			Assets: fstest.MapFS{"dist/swagger-ui-bundle.js": {Data: []byte("//")}},
+		})
+		if err == nil || !strings.Contains(err.Error(), "Assets contains none of the Swagger UI files") {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+}
+
+func Test_Assets_URL(t *testing.T) {
+	app := fiber.New()
+	app.Get("/swag/*", New(Config{
+		Spec:                  BytesSpec([]byte(`{"swagger":"2.0"}`)),
+		AssetsURL:             "https://cdn.example.com/swagger-ui-dist@5.17.14/",
+		ContentSecurityPolicy: &CSPConfig{},
+	}))
+
+	req, err := http.NewRequest(http.MethodGet, "/swag/index.html", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		`href="https://cdn.example.com/swagger-ui-dist@5.17.14/swagger-ui.css"`,
+		`href="https://cdn.example.com/swagger-ui-dist@5.17.14/favicon-32x32.png"`,
+		`src="https://cdn.example.com/swagger-ui-dist@5.17.14/swagger-ui-bundle.js"`,
+		`src="https://cdn.example.com/swagger-ui-dist@5.17.14/swagger-ui-standalone-preset.js"`,
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Fatalf("index.html does not contain %s", expected)
+		}
+	}
+
+	policy := resp.Header.Get("Content-Security-Policy")
+	directives := make(map[string]string)
+	for _, part := range strings.Split(policy, "; ") {
+		name, sources, _ := strings.Cut(part, " ")
+		directives[name] = sources
+	}
+	for _, directive := range []string{"script-src", "style-src", "img-src"} {
+		if !strings.Contains(directives[directive], "https://cdn.example.com") {
+			t.Fatalf("%s does not allow the CDN: %s", directive, policy)
+		}
+	}
+
+	if _, err := NewE(Config{Spec: BytesSpec([]byte(`{"swagger":"2.0"}`)), AssetsURL: "https://cdn.example.com/", Offline: true}); err == nil {
+		t.Fatal("expected an error for an external AssetsURL in offline mode")
+	}
+}
@@ -0,0 +1,140 @@
+package swagger
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// credentialsKey is the key of the Locals holding the Authorization header a request was authorized with,
+// so that the proxy does not forward the documentation credentials
+type credentialsKey struct{}
+
+// BasicAuth returns an Authorizer accepting the HTTP Basic credentials of users, a map of username to password.
+// Rejected requests are challenged for the given realm.
+func BasicAuth(realm string, users map[string]string) func(*fiber.Ctx) (bool, error) {
+	challenge := "Basic realm=" + strconv.Quote(realm)
+
+	return func(c *fiber.Ctx) (bool, error) {
+		auth := c.Get(fiber.HeaderAuthorization)
+		if len(auth) > 6 && strings.EqualFold(auth[:6], "basic ") {
+			if raw, err := base64.StdEncoding.DecodeString(auth[6:]); err == nil {
+				if username, password, ok := strings.Cut(string(raw), ":"); ok {
+					if expected, ok := users[username]; ok && secureCompare(password, expected) {
+						c.Locals(credentialsKey{}, auth)
+						return true, nil
+					}
+				}
+			}
+		}
+
+		c.Set(fiber.HeaderWWWAuthenticate, challenge)
+		return false, nil
+	}
+}
+
+// BearerAuth returns an Authorizer accepting requests with one of the given tokens in a
+// "Authorization: Bearer <token>" header. Rejected requests are challenged with the Bearer scheme.
+func BearerAuth(tokens ...string) func(*fiber.Ctx) (bool, error) {
+	return func(c *fiber.Ctx) (bool, error) {
+		auth := c.Get(fiber.HeaderAuthorization)
+		if len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") && matchesAny(strings.TrimSpace(auth[7:]), tokens) {
+			c.Locals(credentialsKey{}, auth)
+			return true, nil
+		}
+
+		c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+		return false, nil
+	}
+}
+
+// APIKeyAuth returns an Authorizer accepting requests with one of the given keys in the header named header.
+func APIKeyAuth(header string, keys ...string) func(*fiber.Ctx) (bool, error) {
+	return func(c *fiber.Ctx) (bool, error) {
+		return matchesAny(c.Get(header), keys), nil
+	}
+}
+
+// IPAllowlist returns an Authorizer accepting requests from the given IP addresses or CIDR ranges.
+// The client address is the one returned by fiber.Ctx.IP, configure fiber.Config.ProxyHeader behind a proxy.
+// It panics if an entry is neither an IP address nor a CIDR range.
+func IPAllowlist(entries ...string) func(*fiber.Ctx) (bool, error) {
+	networks := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				panic(fmt.Errorf("fiber: swagger middleware error -> invalid IP address %q", entry))
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			panic(fmt.Errorf("fiber: swagger middleware error -> %w", err))
+		}
+		networks = append(networks, network)
+	}
+
+	return func(c *fiber.Ctx) (bool, error) {
+		ip := net.ParseIP(c.IP())
+		if ip == nil {
+			return false, nil
+		}
+		for _, network := range networks {
+			if network.Contains(ip) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// authorize applies the Authorizer of cfg, it returns the error answering rejected requests
+func authorize(c *fiber.Ctx, cfg *Config) error {
+	ok, err := cfg.Authorizer(c)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return nil
+	}
+
+	if cfg.HideUnauthorized {
+		// A challenge would reveal the documentation
+		c.Response().Header.Del(fiber.HeaderWWWAuthenticate)
+		return fiber.ErrNotFound
+	}
+	return fiber.ErrUnauthorized
+}
+
+// matchesAny reports whether value equals one of candidates, in constant time for each of them
+func matchesAny(value string, candidates []string) bool {
+	if value == "" {
+		return false
+	}
+	matched := false
+	for _, candidate := range candidates {
+		if secureCompare(value, candidate) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// secureCompare compares the hashes of a and b so that the duration does not depend on their content or length
+func secureCompare(a, b string) bool {
+	ha, hb := sha256.Sum256([]byte(a)), sha256.Sum256([]byte(b))
+	return subtle.ConstantTimeCompare(ha[:], hb[:]) == 1
+}
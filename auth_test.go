@@ -0,0 +1,157 @@
+package swagger
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_Authorizer(t *testing.T) {
+	spec := BytesSpec([]byte(`{"swagger":"2.0"}`))
+	basic := func(credentials string) string {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	}
+
+	tests := []struct {
+		name            string
+		config          Config
+		url             string
+		headers         map[string]string
+		statusCode      int
+		wwwAuthenticate string
+		cacheControl    string
+	}{
+		{
+			name:         "Basic credentials accepted",
+			config:       Config{Authorizer: BasicAuth("docs", map[string]string{"admin": "secret"})},
+			url:          "/swag/doc.json",
+			headers:      map[string]string{"Authorization": basic("admin:secret")},
+			statusCode:   200,
+			cacheControl: "private, no-cache",
+		},
+		{
+			name:            "Basic credentials rejected",
+			config:          Config{Authorizer: BasicAuth("docs", map[string]string{"admin": "secret"})},
+			url:             "/swag/index.html",
+			headers:         map[string]string{"Authorization": basic("admin:guess")},
+			statusCode:      401,
+			wwwAuthenticate: `Basic realm="docs"`,
+		},
+		{
+			name:            "Static assets are protected",
+			config:          Config{Authorizer: BasicAuth("docs", map[string]string{"admin": "secret"})},
+			url:             "/swag/favicon-16x16.png",
+			statusCode:      401,
+			wwwAuthenticate: `Basic realm="docs"`,
+		},
+		{
+			name:       "Hidden documentation",
+			config:     Config{Authorizer: BasicAuth("docs", map[string]string{"admin": "secret"}), HideUnauthorized: true},
+			url:        "/swag/index.html",
+			statusCode: 404,
+		},
+		{
+			name:       "Bearer token accepted",
+			config:     Config{Authorizer: BearerAuth("t1", "t2")},
+			url:        "/swag/doc.json",
+			headers:    map[string]string{"Authorization": "Bearer t2"},
+			statusCode: 200,
+		},
+		{
+			name:            "Bearer token rejected",
+			config:          Config{Authorizer: BearerAuth("t1", "t2")},
+			url:             "/swag/doc.json",
+			headers:         map[string]string{"Authorization": "Bearer t3"},
+			statusCode:      401,
+			wwwAuthenticate: "Bearer",
+		},
+		{
+			name:       "API key accepted",
+			config:     Config{Authorizer: APIKeyAuth("X-API-Key", "k1")},
+			url:        "/swag/doc.json",
+			headers:    map[string]string{"X-API-Key": "k1"},
+			statusCode: 200,
+		},
+		{
+			name:       "API key missing",
+			config:     Config{Authorizer: APIKeyAuth("X-API-Key", "k1")},
+			url:        "/swag/doc.json",
+			statusCode: 401,
+		},
+		{
+			name:       "IP in allowed range",
+			config:     Config{Authorizer: IPAllowlist("10.0.0.0/8", "192.168.1.10")},
+			url:        "/swag/doc.json",
+			headers:    map[string]string{"X-Real-IP": "10.1.2.3"},
+			statusCode: 200,
+		},
+		{
+			name:       "Allowed IP",
+			config:     Config{Authorizer: IPAllowlist("10.0.0.0/8", "192.168.1.10")},
+			url:        "/swag/doc.json",
+			headers:    map[string]string{"X-Real-IP": "192.168.1.10"},
+			statusCode: 200,
+		},
+		{
+			name:       "IP not allowed",
+			config:     Config{Authorizer: IPAllowlist("10.0.0.0/8", "192.168.1.10")},
+			url:        "/swag/doc.json",
+			headers:    map[string]string{"X-Real-IP": "192.168.1.11"},
+			statusCode: 401,
+		},
+		{
+			name: "Authorizer error",
+			config: Config{Authorizer: func(*fiber.Ctx) (bool, error) {
+				return false, errors.New("backend unavailable")
+			}},
+			url:        "/swag/doc.json",
+			statusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ProxyHeader: "X-Real-IP"})
+			tt.config.Spec = spec
+			app.Get("/swag/*", New(tt.config))
+
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tt.statusCode {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, tt.statusCode)
+			}
+			if challenge := resp.Header.Get("WWW-Authenticate"); challenge != tt.wwwAuthenticate {
+				t.Fatalf(`WWW-Authenticate: got %s - expected %s`, challenge, tt.wwwAuthenticate)
+			}
+			if tt.cacheControl != "" {
+				if cc := resp.Header.Get("Cache-Control"); cc != tt.cacheControl {
+					t.Fatalf(`Cache-Control: got %s - expected %s`, cc, tt.cacheControl)
+				}
+			}
+		})
+	}
+}
+
+func Test_IPAllowlist_Invalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+	}()
+
+	IPAllowlist("10.0.0.0/33")
+}
@@ -0,0 +1,648 @@
+package swagger
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/swaggo/swag"
+)
+
+// Document is an OpenAPI 3 document built at runtime from the routes of an application, an alternative
+// to the documents generated by the swag CLI. Routes registered through it are documented with their
+// path parameters, and with the request and response schemas derived from Go types.
+//
+//	doc := swagger.NewDocument(swagger.Info{Title: "Users", Version: "1.0"})
+//	doc.Get(app, "/users/:id<int>", swagger.Operation{
+//		Summary:   "Get a user",
+//		Responses: []swagger.Response{{Status: 200, Body: User{}}},
+//	}, getUser)
+//	doc.Register("swagger")
+//
+// Documents are safe for concurrent use, routes registered after the document was served appear on the next read.
+type Document struct {
+	mu      sync.RWMutex
+	info    Info
+	paths   map[string]map[string]interface{}
+	schemas map[string]interface{}
+	// Names of the schemas of named Go types under components/schemas
+	types map[reflect.Type]string
+}
+
+// Info describes the API of a Document.
+type Info struct {
+	// Title of the API.
+	// default: "API"
+	Title string
+
+	// Version of the API.
+	// default: "1.0"
+	Version string
+
+	// Description of the API, CommonMark is allowed.
+	// default: ""
+	Description string
+
+	// URLs of the servers of the API, e.g. "https://api.example.com/v1".
+	// default: nil
+	Servers []string
+}
+
+// Operation describes a route registered through a Document.
+type Operation struct {
+	// Unique identifier of the operation.
+	// default: ""
+	ID string
+
+	// Short summary of the operation.
+	// default: ""
+	Summary string
+
+	// Description of the operation, CommonMark is allowed.
+	// default: ""
+	Description string
+
+	// Tags grouping the operation in the viewers.
+	// default: nil
+	Tags []string
+
+	// Marks the operation as deprecated.
+	// default: false
+	Deprecated bool
+
+	// Parameters of the operation. Path parameters are inferred from the route and only need
+	// an entry to be described or typed.
+	// default: nil
+	Params []Param
+
+	// Value of the Go type of the request body, e.g. CreateUser{}.
+	// default: nil -> no request body
+	Body interface{}
+
+	// Media type of the request body.
+	// default: "application/json"
+	BodyContentType string
+
+	// Responses of the operation.
+	// default: nil -> a single "200 OK" response without content
+	Responses []Response
+}
+
+// Param describes a parameter of an Operation.
+type Param struct {
+	// Name of the parameter.
+	Name string
+
+	// Location of the parameter, "query", "header", "path" or "cookie".
+	// default: "query"
+	In string
+
+	// Description of the parameter.
+	// default: ""
+	Description string
+
+	// Marks the parameter as required, path parameters always are.
+	// default: false
+	Required bool
+
+	// Value of the Go type of the parameter, e.g. 0 or []string{}.
+	// default: nil -> the type of the route constraint, or string
+	Type interface{}
+}
+
+// Response describes a response of an Operation.
+type Response struct {
+	// Status code of the response.
+	// default: 0 -> the default response
+	Status int
+
+	// Description of the response.
+	// default: the status text
+	Description string
+
+	// Value of the Go type of the response body, e.g. User{} or []User{}.
+	// default: nil -> no content
+	Body interface{}
+
+	// Media type of the response body.
+	// default: "application/json"
+	ContentType string
+}
+
+// NewDocument returns an empty document describing the API info.
+func NewDocument(info Info) *Document {
+	if info.Title == "" {
+		info.Title = "API"
+	}
+	if info.Version == "" {
+		info.Version = "1.0"
+	}
+	return &Document{
+		info:    info,
+		paths:   make(map[string]map[string]interface{}),
+		schemas: make(map[string]interface{}),
+		types:   make(map[reflect.Type]string),
+	}
+}
+
+// Add registers a route on router, like router.Add, and documents it with op.
+func (d *Document) Add(router fiber.Router, method, path string, op Operation, handlers ...fiber.Handler) fiber.Router {
+	d.addOperation(strings.ToLower(method), joinPath(routerPrefix(router), path), op)
+	return router.Add(method, path, handlers...)
+}
+
+// Get registers a GET route on router and documents it with op.
+func (d *Document) Get(router fiber.Router, path string, op Operation, handlers ...fiber.Handler) fiber.Router {
+	return d.Add(router, fiber.MethodGet, path, op, handlers...)
+}
+
+// Post registers a POST route on router and documents it with op.
+func (d *Document) Post(router fiber.Router, path string, op Operation, handlers ...fiber.Handler) fiber.Router {
+	return d.Add(router, fiber.MethodPost, path, op, handlers...)
+}
+
+// Put registers a PUT route on router and documents it with op.
+func (d *Document) Put(router fiber.Router, path string, op Operation, handlers ...fiber.Handler) fiber.Router {
+	return d.Add(router, fiber.MethodPut, path, op, handlers...)
+}
+
+// Patch registers a PATCH route on router and documents it with op.
+func (d *Document) Patch(router fiber.Router, path string, op Operation, handlers ...fiber.Handler) fiber.Router {
+	return d.Add(router, fiber.MethodPatch, path, op, handlers...)
+}
+
+// Delete registers a DELETE route on router and documents it with op.
+func (d *Document) Delete(router fiber.Router, path string, op Operation, handlers ...fiber.Handler) fiber.Router {
+	return d.Add(router, fiber.MethodDelete, path, op, handlers...)
+}
+
+// Register registers the document as the swag instance named instanceName, served by a handler
+// with the same InstanceName. Like swag.Register, it panics when the name is already registered.
+func (d *Document) Register(instanceName string) {
+	if instanceName == "" {
+		instanceName = swag.Name
+	}
+	swag.Register(instanceName, d)
+}
+
+// ReadDoc returns the document as JSON, it implements swag.Swagger.
+func (d *Document) ReadDoc() string {
+	doc, _ := d.marshal()
+	return string(doc)
+}
+
+// Read returns the document as JSON, it implements Spec.
+func (d *Document) Read(_ context.Context) ([]byte, string, error) {
+	doc, err := d.marshal()
+	if err != nil {
+		return nil, "", err
+	}
+	return doc, fiber.MIMEApplicationJSON, nil
+}
+
+func (d *Document) marshal() ([]byte, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	info := map[string]interface{}{"title": d.info.Title, "version": d.info.Version}
+	if d.info.Description != "" {
+		info["description"] = d.info.Description
+	}
+	root := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    info,
+		"paths":   d.paths,
+	}
+	if len(d.info.Servers) > 0 {
+		servers := make([]interface{}, len(d.info.Servers))
+		for i, u := range d.info.Servers {
+			servers[i] = map[string]interface{}{"url": u}
+		}
+		root["servers"] = servers
+	}
+	if len(d.schemas) > 0 {
+		root["components"] = map[string]interface{}{"schemas": d.schemas}
+	}
+	return marshalJSON(root)
+}
+
+func (d *Document) addOperation(method, route string, op Operation) {
+	if !containsString(operationMethods, method) {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	template, names, inferred := routeTemplate(route)
+
+	operation := make(map[string]interface{})
+	if op.ID != "" {
+		operation["operationId"] = op.ID
+	}
+	if op.Summary != "" {
+		operation["summary"] = op.Summary
+	}
+	if op.Description != "" {
+		operation["description"] = op.Description
+	}
+	if len(op.Tags) > 0 {
+		operation["tags"] = op.Tags
+	}
+	if op.Deprecated {
+		operation["deprecated"] = true
+	}
+
+	// Parameters described by the operation replace the inferred ones of the same name
+	described := make(map[string]bool, len(op.Params))
+	var params []interface{}
+	for _, p := range op.Params {
+		in := p.In
+		if in == "" {
+			in = "query"
+		}
+		param := map[string]interface{}{"name": p.Name, "in": in}
+		if p.Description != "" {
+			param["description"] = p.Description
+		}
+		if p.Required || in == "path" {
+			param["required"] = true
+		}
+		schema := map[string]interface{}{"type": "string"}
+		if p.Type != nil {
+			schema = d.schemaFor(reflect.TypeOf(p.Type))
+		} else if in == "path" && inferred[p.Name] != nil {
+			schema = inferred[p.Name]
+		}
+		param["schema"] = schema
+		if in == "path" {
+			described[p.Name] = true
+		}
+		params = append(params, param)
+	}
+	for _, name := range names {
+		if !described[name] {
+			params = append(params, map[string]interface{}{"name": name, "in": "path", "required": true, "schema": inferred[name]})
+		}
+	}
+	if len(params) > 0 {
+		operation["parameters"] = params
+	}
+
+	if op.Body != nil {
+		ct := op.BodyContentType
+		if ct == "" {
+			ct = fiber.MIMEApplicationJSON
+		}
+		operation["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  map[string]interface{}{ct: map[string]interface{}{"schema": d.schemaFor(reflect.TypeOf(op.Body))}},
+		}
+	}
+
+	responses := make(map[string]interface{})
+	for _, r := range op.Responses {
+		code := "default"
+		description := r.Description
+		if r.Status != 0 {
+			code = strconv.Itoa(r.Status)
+			if description == "" {
+				description = utils.StatusMessage(r.Status)
+			}
+		}
+		if description == "" {
+			description = "Default response"
+		}
+		response := map[string]interface{}{"description": description}
+		if r.Body != nil {
+			ct := r.ContentType
+			if ct == "" {
+				ct = fiber.MIMEApplicationJSON
+			}
+			response["content"] = map[string]interface{}{ct: map[string]interface{}{"schema": d.schemaFor(reflect.TypeOf(r.Body))}}
+		}
+		responses[code] = response
+	}
+	if len(responses) == 0 {
+		responses[strconv.Itoa(fiber.StatusOK)] = map[string]interface{}{"description": utils.StatusMessage(fiber.StatusOK)}
+	}
+	operation["responses"] = responses
+
+	item := d.paths[template]
+	if item == nil {
+		item = make(map[string]interface{})
+		d.paths[template] = item
+	}
+	item[method] = operation
+}
+
+// routerPrefix returns the path prefix of the routes registered on router
+func routerPrefix(router fiber.Router) string {
+	if grp, ok := router.(*fiber.Group); ok {
+		return grp.Prefix
+	}
+	return ""
+}
+
+// Parameters of a Fiber route: ":name" with an optional constraint and "?" suffix, and the "*" and "+" wildcards
+var routeParamRe = regexp.MustCompile(`:([A-Za-z0-9_]+)(<[^>]*>)?\??|[*+]`)
+
+// routeTemplate converts a Fiber route to an OpenAPI path template, along with the names and schemas of its parameters.
+// Optional parameters are documented as required, OpenAPI having no optional path segments.
+func routeTemplate(route string) (string, []string, map[string]map[string]interface{}) {
+	var names []string
+	params := make(map[string]map[string]interface{})
+	wildcards := 0
+	template := routeParamRe.ReplaceAllStringFunc(route, func(match string) string {
+		if match == "*" || match == "+" {
+			wildcards++
+			name := "wildcard"
+			if wildcards > 1 {
+				name += strconv.Itoa(wildcards)
+			}
+			names = append(names, name)
+			params[name] = map[string]interface{}{"type": "string"}
+			return "{" + name + "}"
+		}
+		sub := routeParamRe.FindStringSubmatch(match)
+		names = append(names, sub[1])
+		params[sub[1]] = constraintSchema(strings.Trim(sub[2], "<>"))
+		return "{" + sub[1] + "}"
+	})
+	return template, names, params
+}
+
+// layoutFormat returns the OpenAPI format of values in the Go time layout, or "" if there is none
+func layoutFormat(layout string) string {
+	switch layout {
+	case "2006-01-02":
+		return "date"
+	case time.RFC3339, time.RFC3339Nano:
+		return "date-time"
+	}
+	return ""
+}
+
+// constraintSchema returns the schema of a route parameter with Fiber constraints such as "int;min(1)"
+func constraintSchema(constraints string) map[string]interface{} {
+	schema := map[string]interface{}{"type": "string"}
+	if constraints == "" {
+		return schema
+	}
+	for _, c := range strings.Split(constraints, ";") {
+		name, arg, _ := strings.Cut(strings.TrimSuffix(c, ")"), "(")
+		args := strings.Split(arg, ",")
+		number := func(i int) interface{} {
+			if i >= len(args) {
+				return nil
+			}
+			return tagValue("number", strings.TrimSpace(args[i]))
+		}
+
+		switch name {
+		case "int":
+			schema["type"] = "integer"
+		case "float":
+			schema["type"] = "number"
+		case "bool":
+			schema["type"] = "boolean"
+		case "alpha":
+			schema["pattern"] = "^[a-zA-Z]+$"
+		case "guid":
+			schema["format"] = "uuid"
+		case "datetime":
+			// The argument is a Go layout, only the ones matching a format of OpenAPI are documented
+			if format := layoutFormat(strings.ReplaceAll(arg, `\`, "")); format != "" {
+				schema["format"] = format
+			}
+		case "regex":
+			schema["pattern"] = arg
+		case "minLen":
+			schema["minLength"] = number(0)
+		case "maxLen":
+			schema["maxLength"] = number(0)
+		case "len":
+			schema["minLength"], schema["maxLength"] = number(0), number(0)
+		case "betweenLen":
+			schema["minLength"], schema["maxLength"] = number(0), number(1)
+		case "min":
+			schema["type"], schema["minimum"] = "integer", number(0)
+		case "max":
+			schema["type"], schema["maximum"] = "integer", number(0)
+		case "range":
+			schema["type"], schema["minimum"], schema["maximum"] = "integer", number(0), number(1)
+		}
+	}
+	return schema
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	schemaNameRe      = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// schemaFor returns the schema of a Go type, named struct types are referenced from components/schemas
+func (d *Document) schemaFor(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == rawMessageType:
+		return map[string]interface{}{}
+	case t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType):
+		return map[string]interface{}{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uintptr:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": d.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": d.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.structSchema(t)
+		}
+		name, ok := d.types[t]
+		if !ok {
+			name = d.schemaName(t)
+			d.types[t] = name
+			// Registered before the fields are visited, for recursive types
+			d.schemas[name] = map[string]interface{}{}
+			d.schemas[name] = d.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + escapePointer(name)}
+	}
+	return map[string]interface{}{}
+}
+
+// schemaName returns an unused name for the schema of a named type
+func (d *Document) schemaName(t reflect.Type) string {
+	name := schemaNameRe.ReplaceAllString(t.Name(), "_")
+	if _, taken := d.schemas[name]; !taken {
+		return name
+	}
+	name = path.Base(t.PkgPath()) + "." + name
+	candidate := name
+	for i := 2; ; i++ {
+		if _, taken := d.schemas[candidate]; !taken {
+			return candidate
+		}
+		candidate = name + strconv.Itoa(i)
+	}
+}
+
+// structSchema returns the object schema of a struct type, following the encoding/json field rules
+func (d *Document) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || f.Tag.Get("swaggerignore") == "true" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		// Fields of embedded structs are promoted
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			embedded := d.structSchema(ft)
+			for k, v := range mapOf(embedded["properties"]) {
+				if _, ok := properties[k]; !ok {
+					properties[k] = v
+				}
+			}
+			if names, ok := embedded["required"].([]string); ok {
+				required = append(required, names...)
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		schema := d.schemaFor(f.Type)
+		if strings.Contains(","+opts+",", ",string,") {
+			schema = map[string]interface{}{"type": "string"}
+		}
+		if schema["$ref"] == nil {
+			applyFieldTags(schema, f.Tag)
+		} else if description := f.Tag.Get("description"); description != "" {
+			// Siblings of $ref are ignored by OpenAPI 3.0
+			schema = map[string]interface{}{"allOf": []interface{}{schema}, "description": description}
+		}
+		properties[name] = schema
+
+		if isRequiredField(f.Tag) {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// isRequiredField reports whether a validate or binding tag requires the field, like the swag CLI does
+func isRequiredField(tag reflect.StructTag) bool {
+	for _, key := range []string{"validate", "binding"} {
+		for _, rule := range strings.Split(tag.Get(key), ",") {
+			if rule == "required" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyFieldTags copies the keywords of the swag struct tags to schema
+func applyFieldTags(schema map[string]interface{}, tag reflect.StructTag) {
+	typ, _ := schema["type"].(string)
+	if items := mapOf(schema["items"]); typ == "array" && items != nil {
+		if enums := tag.Get("enums"); enums != "" {
+			applyEnums(items, enums)
+		}
+	} else if enums := tag.Get("enums"); enums != "" {
+		applyEnums(schema, enums)
+	}
+
+	for _, key := range []string{"description", "format"} {
+		if v := tag.Get(key); v != "" {
+			schema[key] = v
+		}
+	}
+	for _, key := range []string{"example", "default"} {
+		if v, ok := tag.Lookup(key); ok {
+			schema[key] = tagValue(typ, v)
+		}
+	}
+	for _, key := range []string{"minimum", "maximum", "minLength", "maxLength"} {
+		if v := tag.Get(key); v != "" {
+			schema[key] = tagValue("number", v)
+		}
+	}
+}
+
+func applyEnums(schema map[string]interface{}, enums string) {
+	typ, _ := schema["type"].(string)
+	var values []interface{}
+	for _, v := range strings.Split(enums, ",") {
+		values = append(values, tagValue(typ, strings.TrimSpace(v)))
+	}
+	schema["enum"] = values
+}
+
+// tagValue converts the value of a struct tag to the JSON type of a schema, values which do not parse stay strings
+func tagValue(typ, v string) interface{} {
+	switch typ {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(v, 64); err == nil {
+			return json.Number(v)
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return v
+}
@@ -0,0 +1,187 @@
+package swagger
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type builderEntity struct {
+	ID      int64     `json:"id" validate:"required"`
+	Created time.Time `json:"created"`
+}
+
+type builderUser struct {
+	builderEntity
+	Name     string         `json:"name" validate:"required,min=1" example:"Ada" maxLength:"64"`
+	Role     string         `json:"role,omitempty" enums:"admin,member" default:"member"`
+	Age      int32          `json:"age,string"`
+	Score    *float64       `json:"score"`
+	Tags     []string       `json:"tags"`
+	Meta     map[string]int `json:"meta"`
+	Manager  *builderUser   `json:"manager,omitempty" description:"Manager of the user"`
+	Password string         `json:"-"`
+	internal string
+}
+
+type builderCreateUser struct {
+	Name   string `json:"name" binding:"required"`
+	Avatar []byte `json:"avatar"`
+}
+
+func Test_Document(t *testing.T) {
+	doc := NewDocument(Info{Title: "Users", Servers: []string{"https://api.example.com"}})
+
+	app := fiber.New()
+	api := app.Group("/api")
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(200) }
+
+	doc.Get(api, "/users/:id<int;min(1)>", Operation{
+		ID:        "getUser",
+		Summary:   "Get a user",
+		Tags:      []string{"users"},
+		Params:    []Param{{Name: "fields", Description: "Fields to return", Type: []string{}}},
+		Responses: []Response{{Status: 200, Body: builderUser{}}, {Status: 404}},
+	}, ok)
+	doc.Post(api, "/users", Operation{
+		Body:      builderCreateUser{},
+		Responses: []Response{{Status: 201, Body: &builderUser{}}},
+	}, ok)
+	doc.Get(app, "/files/+", Operation{}, ok)
+	doc.Add(app, "CONNECT", "/tunnel", Operation{}, ok)
+
+	var root map[string]interface{}
+	if err := json.Unmarshal([]byte(doc.ReadDoc()), &root); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Should document the routes", func(t *testing.T) {
+		paths := mapOf(root["paths"])
+		expected := []string{"/api/users", "/api/users/{id}", "/files/{wildcard}"}
+		if keys := sortedKeys(paths); !reflect.DeepEqual(keys, expected) {
+			t.Fatalf("paths: got %v - expected %v", keys, expected)
+		}
+
+		get := mapOf(mapOf(paths["/api/users/{id}"])["get"])
+		params, _ := json.Marshal(get["parameters"])
+		expectedParams := `[{"description":"Fields to return","in":"query","name":"fields","schema":{"items":{"type":"string"},"type":"array"}},` +
+			`{"in":"path","name":"id","required":true,"schema":{"minimum":1,"type":"integer"}}]`
+		if string(params) != expectedParams {
+			t.Fatalf("parameters: got %s - expected %s", params, expectedParams)
+		}
+
+		responses, _ := json.Marshal(get["responses"])
+		expectedResponses := `{"200":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/builderUser"}}},"description":"OK"},` +
+			`"404":{"description":"Not Found"}}`
+		if string(responses) != expectedResponses {
+			t.Fatalf("responses: got %s - expected %s", responses, expectedResponses)
+		}
+
+		post := mapOf(mapOf(paths["/api/users"])["post"])
+		body, _ := json.Marshal(post["requestBody"])
+		expectedBody := `{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/builderCreateUser"}}},"required":true}`
+		if string(body) != expectedBody {
+			t.Fatalf("requestBody: got %s - expected %s", body, expectedBody)
+		}
+	})
+
+	t.Run("Should derive the schemas from Go types", func(t *testing.T) {
+		schemas := mapOf(mapOf(root["components"])["schemas"])
+
+		user, _ := json.Marshal(schemas["builderUser"])
+		expectedUser := `{"properties":{` +
+			`"age":{"type":"string"},` +
+			`"created":{"format":"date-time","type":"string"},` +
+			`"id":{"format":"int64","type":"integer"},` +
+			`"manager":{"allOf":[{"$ref":"#/components/schemas/builderUser"}],"description":"Manager of the user"},` +
+			`"meta":{"additionalProperties":{"type":"integer"},"type":"object"},` +
+			`"name":{"example":"Ada","maxLength":64,"type":"string"},` +
+			`"role":{"default":"member","enum":["admin","member"],"type":"string"},` +
+			`"score":{"format":"double","type":"number"},` +
+			`"tags":{"items":{"type":"string"},"type":"array"}` +
+			`},"required":["id","name"],"type":"object"}`
+		if string(user) != expectedUser {
+			t.Fatalf("builderUser: got %s - expected %s", user, expectedUser)
+		}
+
+		create, _ := json.Marshal(schemas["builderCreateUser"])
+		expectedCreate := `{"properties":{"avatar":{"format":"byte","type":"string"},"name":{"type":"string"}},"required":["name"],"type":"object"}`
+		if string(create) != expectedCreate {
+			t.Fatalf("builderCreateUser: got %s - expected %s", create, expectedCreate)
+		}
+	})
+
+	t.Run("Should serve a valid document", func(t *testing.T) {
+		if err := Validate(Config{Spec: doc}); err != nil {
+			t.Fatal(err)
+		}
+
+		app.Get("/swag/*", New(Config{Spec: doc}))
+		req, err := http.NewRequest(http.MethodGet, "/swag/doc.json", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != 200 {
+			t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 200)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != doc.ReadDoc() {
+			t.Fatalf("Body: got %s - expected %s", body, doc.ReadDoc())
+		}
+	})
+}
+
+func Test_Route_Template(t *testing.T) {
+	tests := []struct {
+		route    string
+		template string
+		names    []string
+	}{
+		{route: "/users/:id", template: "/users/{id}", names: []string{"id"}},
+		{route: "/users/:id?", template: "/users/{id}", names: []string{"id"}},
+		{route: "/flights/:from-:to", template: "/flights/{from}-{to}", names: []string{"from", "to"}},
+		{route: "/items/:id<guid>/*", template: "/items/{id}/{wildcard}", names: []string{"id", "wildcard"}},
+		{route: "/a/*/b/*", template: "/a/{wildcard}/b/{wildcard2}", names: []string{"wildcard", "wildcard2"}},
+	}
+
+	for _, tt := range tests {
+		template, names, _ := routeTemplate(tt.route)
+		if template != tt.template {
+			t.Fatalf("routeTemplate(%s): got %s - expected %s", tt.route, template, tt.template)
+		}
+		if !reflect.DeepEqual(names, tt.names) {
+			t.Fatalf("routeTemplate(%s) names: got %v - expected %v", tt.route, names, tt.names)
+		}
+	}
+}
+
+func Test_Constraint_Schema(t *testing.T) {
+	tests := []struct {
+		constraints string
+		schema      map[string]interface{}
+	}{
+		{constraints: "int;min(1)", schema: map[string]interface{}{"type": "integer", "minimum": json.Number("1")}},
+		{constraints: `datetime(2006\-01\-02)`, schema: map[string]interface{}{"type": "string", "format": "date"}},
+		{constraints: "datetime(2006-01-02T15:04:05Z07:00)", schema: map[string]interface{}{"type": "string", "format": "date-time"}},
+		{constraints: "datetime(02.01.2006)", schema: map[string]interface{}{"type": "string"}},
+	}
+
+	for _, tt := range tests {
+		schema := constraintSchema(tt.constraints)
+		if !reflect.DeepEqual(schema, tt.schema) {
+			t.Fatalf("constraintSchema(%s): got %v - expected %v", tt.constraints, schema, tt.schema)
+		}
+	}
+}
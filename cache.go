@@ -0,0 +1,153 @@
+package swagger
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// rendition is a representation of a document ready to be sent
+type rendition struct {
+	body    []byte
+	etag    string
+	modTime time.Time
+
+	// Built for a single request, compressed at the fastest levels since the work is not reused
+	transient bool
+
+	// Compressed bodies by content coding
+	mu        sync.Mutex
+	encodings map[string][]byte
+}
+
+func newRendition(body []byte, modTime time.Time) *rendition {
+	sum := sha256.Sum256(body)
+	return &rendition{
+		body:    body,
+		etag:    `"` + hex.EncodeToString(sum[:16]) + `"`,
+		modTime: modTime,
+	}
+}
+
+// newTransientRendition returns a rendition built for a single request
+func newTransientRendition(body []byte, modTime time.Time) *rendition {
+	r := newRendition(body, modTime)
+	r.transient = true
+	return r
+}
+
+// docCache memoizes the renditions of the last document it has seen.
+// The cache is reset as soon as the source document changes.
+type docCache struct {
+	mu      sync.RWMutex
+	source  []byte
+	modTime time.Time
+	formats map[string]*rendition
+}
+
+// get returns the cached rendition of doc for the given format, calling convert
+// to build it when it is missing or stale. A nil convert serves doc unchanged.
+func (dc *docCache) get(doc []byte, format string, convert func([]byte) ([]byte, error)) (*rendition, error) {
+	dc.mu.RLock()
+	r, ok := dc.formats[format]
+	fresh := bytes.Equal(dc.source, doc)
+	dc.mu.RUnlock()
+
+	if ok && fresh {
+		return r, nil
+	}
+
+	out := doc
+	if convert != nil {
+		var err error
+		if out, err = convert(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	if !bytes.Equal(dc.source, doc) {
+		dc.source = append([]byte(nil), doc...)
+		dc.modTime = time.Now().UTC().Truncate(time.Second)
+		dc.formats = make(map[string]*rendition)
+	}
+
+	r = newRendition(out, dc.modTime)
+	dc.formats[format] = r
+	return r, nil
+}
+
+// delivery holds the options applied to every rendition sent by the handler
+type delivery struct {
+	cacheControl string
+	compress     bool
+}
+
+// send writes r with its validators, answering conditional requests with 304 Not Modified
+func (dl delivery) send(c *fiber.Ctx, r *rendition) error {
+	body, etag := r.body, r.etag
+
+	if dl.compress {
+		c.Vary(fiber.HeaderAcceptEncoding)
+		if encoding := negotiateEncoding(c); encoding != "" {
+			if out := r.encoded(encoding); out != nil {
+				// Every representation needs its own strong validator
+				body, etag = out, strings.TrimSuffix(etag, `"`)+"-"+encoding+`"`
+				c.Set(fiber.HeaderContentEncoding, encoding)
+			}
+		}
+	}
+
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderLastModified, r.modTime.Format(http.TimeFormat))
+	if dl.cacheControl != "" {
+		c.Set(fiber.HeaderCacheControl, dl.cacheControl)
+	}
+
+	if notModified(c, etag, r.modTime) {
+		c.Response().Header.Del(fiber.HeaderContentEncoding)
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	return c.Send(body)
+}
+
+// notModified evaluates the If-None-Match and If-Modified-Since preconditions of the request
+func notModified(c *fiber.Ctx, etag string, modTime time.Time) bool {
+	if noneMatch := c.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		for _, tag := range strings.Split(noneMatch, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if modifiedSince := c.Get(fiber.HeaderIfModifiedSince); modifiedSince != "" {
+		t, err := http.ParseTime(modifiedSince)
+		return err == nil && !modTime.After(t)
+	}
+
+	return false
+}
+
+// privateCacheControl returns the Cache-Control value cc restricted to the cache of the browser
+func privateCacheControl(cc string) string {
+	directives := []string{"private"}
+	for _, directive := range strings.Split(cc, ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "" || strings.EqualFold(directive, "public") || strings.EqualFold(directive, "private") {
+			continue
+		}
+		directives = append(directives, directive)
+	}
+	return strings.Join(directives, ", ")
+}
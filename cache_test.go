@@ -0,0 +1,92 @@
+package swagger
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_Conditional_Requests(t *testing.T) {
+	var version int32
+	spec := SpecFunc(func(_ context.Context) ([]byte, string, error) {
+		if atomic.LoadInt32(&version) == 0 {
+			return []byte(`{"swagger":"2.0","info":{"version":"1.0"}}`), "", nil
+		}
+		return []byte(`{"swagger":"2.0","info":{"version":"2.0"}}`), "", nil
+	})
+
+	app := fiber.New()
+	app.Get("/swag/*", New(Config{Spec: spec}))
+
+	get := func(t *testing.T, url string, headers map[string]string) *http.Response {
+		t.Helper()
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	for _, url := range []string{"/swag/doc.json", "/swag/doc.yaml", "/swag/index.html"} {
+		t.Run(url, func(t *testing.T) {
+			resp := get(t, url, nil)
+			if resp.StatusCode != 200 {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 200)
+			}
+
+			etag := resp.Header.Get("ETag")
+			if etag == "" {
+				t.Fatal("ETag header is missing")
+			}
+			if cc := resp.Header.Get("Cache-Control"); cc != "no-cache" {
+				t.Fatalf(`Cache-Control: got %s - expected %s`, cc, "no-cache")
+			}
+			lastModified := resp.Header.Get("Last-Modified")
+			if _, err := http.ParseTime(lastModified); err != nil {
+				t.Fatalf("invalid Last-Modified %q: %v", lastModified, err)
+			}
+
+			if resp := get(t, url, map[string]string{"If-None-Match": `"other", ` + etag}); resp.StatusCode != 304 {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 304)
+			}
+			if resp := get(t, url, map[string]string{"If-None-Match": `"other"`}); resp.StatusCode != 200 {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 200)
+			}
+			if resp := get(t, url, map[string]string{"If-Modified-Since": lastModified}); resp.StatusCode != 304 {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 304)
+			}
+			past := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+			if resp := get(t, url, map[string]string{"If-Modified-Since": past}); resp.StatusCode != 200 {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 200)
+			}
+		})
+	}
+
+	t.Run("Should change the ETag with the document", func(t *testing.T) {
+		etag := get(t, "/swag/doc.json", nil).Header.Get("ETag")
+
+		atomic.StoreInt32(&version, 1)
+		defer atomic.StoreInt32(&version, 0)
+
+		resp := get(t, "/swag/doc.json", map[string]string{"If-None-Match": etag})
+		if resp.StatusCode != 200 {
+			t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 200)
+		}
+		if resp.Header.Get("ETag") == etag {
+			t.Fatal("ETag did not change")
+		}
+	})
+}
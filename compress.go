@@ -0,0 +1,168 @@
+package swagger
+
+import (
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	encodingBrotli = "br"
+	encodingZstd   = "zstd"
+	encodingGzip   = "gzip"
+)
+
+// Supported content codings in order of preference
+var encodings = []string{encodingBrotli, encodingZstd, encodingGzip}
+
+// Static assets worth compressing
+var compressibleExts = map[string]bool{
+	".css":  true,
+	".html": true,
+	".js":   true,
+	".json": true,
+	".map":  true,
+}
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+
+	zstdFastOnce    sync.Once
+	zstdFastEncoder *zstd.Encoder
+)
+
+// compress returns body encoded with the given content coding. fast trades the size for the speed,
+// for bodies built for a single request.
+func compress(encoding string, body []byte, fast bool) []byte {
+	switch encoding {
+	case encodingBrotli:
+		if fast {
+			return fasthttp.AppendBrotliBytesLevel(nil, body, fasthttp.CompressBrotliBestSpeed)
+		}
+		// The best brotli level is an order of magnitude slower for a marginal gain
+		return fasthttp.AppendBrotliBytesLevel(nil, body, 9)
+	case encodingZstd:
+		if fast {
+			zstdFastOnce.Do(func() {
+				zstdFastEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest))
+			})
+			return zstdFastEncoder.EncodeAll(body, nil)
+		}
+		zstdOnce.Do(func() {
+			// Only fails on invalid options
+			zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBestCompression))
+		})
+		return zstdEncoder.EncodeAll(body, nil)
+	case encodingGzip:
+		if fast {
+			return fasthttp.AppendGzipBytesLevel(nil, body, fasthttp.CompressBestSpeed)
+		}
+		return fasthttp.AppendGzipBytesLevel(nil, body, fasthttp.CompressBestCompression)
+	}
+	return nil
+}
+
+// encoded returns the body of r in the given content coding, compressing it on first use.
+// It returns nil when the encoding does not make the body smaller.
+func (r *rendition) encoded(encoding string) []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out, ok := r.encodings[encoding]
+	if !ok {
+		out = compress(encoding, r.body, r.transient)
+		if len(out) >= len(r.body) {
+			out = nil
+		}
+		if r.encodings == nil {
+			r.encodings = make(map[string][]byte, len(encodings))
+		}
+		r.encodings[encoding] = out
+	}
+	return out
+}
+
+// negotiateEncoding returns the content coding with the highest quality in the Accept-Encoding
+// header of the request, or an empty string for identity. Ties are broken by the order of encodings.
+func negotiateEncoding(c *fiber.Ctx) string {
+	header := c.Get(fiber.HeaderAcceptEncoding)
+	if header == "" {
+		return ""
+	}
+
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		q := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			var err error
+			if q, err = strconv.ParseFloat(params[2:], 64); err != nil {
+				continue
+			}
+		}
+		qualities[coding] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, encoding := range encodings {
+		q, ok := qualities[encoding]
+		if !ok {
+			q = qualities["*"]
+		}
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
+
+// assetCache keeps the compressible static assets of a file system in memory
+type assetCache struct {
+	fsys    fs.FS
+	modTime time.Time
+	mu      sync.RWMutex
+	files   map[string]*rendition
+}
+
+func newAssetCache(fsys fs.FS) *assetCache {
+	return &assetCache{
+		fsys:    fsys,
+		modTime: time.Now().UTC().Truncate(time.Second),
+		files:   make(map[string]*rendition),
+	}
+}
+
+// get returns the rendition of the asset name, or nil if it is missing or not compressible
+func (ac *assetCache) get(name string) *rendition {
+	if !compressibleExts[path.Ext(name)] {
+		return nil
+	}
+
+	ac.mu.RLock()
+	r, ok := ac.files[name]
+	ac.mu.RUnlock()
+	if ok {
+		return r
+	}
+
+	body, err := fs.ReadFile(ac.fsys, name)
+	if err != nil {
+		return nil
+	}
+	r = newRendition(body, ac.modTime)
+
+	ac.mu.Lock()
+	ac.files[name] = r
+	ac.mu.Unlock()
+
+	return r
+}
@@ -0,0 +1,214 @@
+package swagger
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/klauspost/compress/zstd"
+	swaggerFiles "github.com/swaggo/files/v2"
+	"github.com/valyala/fasthttp"
+)
+
+func Test_Compression(t *testing.T) {
+	app := fiber.New()
+	app.Get("/swag/*", New(Config{
+		Spec:     BytesSpec([]byte(`{"swagger":"2.0","info":{"title":"` + strings.Repeat("Compressible ", 100) + `"}}`)),
+		Compress: true,
+	}))
+
+	bundle, err := fs.ReadFile(swaggerFiles.FS, "swagger-ui-bundle.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decode := map[string]func([]byte) ([]byte, error){
+		"": func(b []byte) ([]byte, error) {
+			return b, nil
+		},
+		"gzip": func(b []byte) ([]byte, error) {
+			return fasthttp.AppendGunzipBytes(nil, b)
+		},
+		"br": func(b []byte) ([]byte, error) {
+			return fasthttp.AppendUnbrotliBytes(nil, b)
+		},
+		"zstd": func(b []byte) ([]byte, error) {
+			dec, err := zstd.NewReader(nil)
+			if err != nil {
+				return nil, err
+			}
+			defer dec.Close()
+			return dec.DecodeAll(b, nil)
+		},
+	}
+
+	tests := []struct {
+		name           string
+		url            string
+		acceptEncoding string
+		encoding       string
+		contentType    string
+		body           []byte
+	}{
+		{
+			name:           "Brotli bundle",
+			url:            "/swag/swagger-ui-bundle.js",
+			acceptEncoding: "gzip, deflate, br",
+			encoding:       "br",
+			contentType:    "text/javascript",
+			body:           bundle,
+		},
+		{
+			name:           "Zstd bundle",
+			url:            "/swag/swagger-ui-bundle.js",
+			acceptEncoding: "zstd",
+			encoding:       "zstd",
+			contentType:    "text/javascript",
+			body:           bundle,
+		},
+		{
+			name:           "Gzip bundle",
+			url:            "/swag/swagger-ui-bundle.js",
+			acceptEncoding: "gzip;q=1.0, br;q=0.5, *;q=0",
+			encoding:       "gzip",
+			contentType:    "text/javascript",
+			body:           bundle,
+		},
+		{
+			name:        "Uncompressed bundle",
+			url:         "/swag/swagger-ui-bundle.js",
+			contentType: "text/javascript",
+			body:        bundle,
+		},
+		{
+			name:           "Wildcard encoding",
+			url:            "/swag/swagger-ui-bundle.js",
+			acceptEncoding: "*",
+			encoding:       "br",
+			contentType:    "text/javascript",
+			body:           bundle,
+		},
+		{
+			name:           "Unsupported encoding",
+			url:            "/swag/swagger-ui-bundle.js",
+			acceptEncoding: "deflate",
+			contentType:    "text/javascript",
+			body:           bundle,
+		},
+		{
+			name:           "Gzip document",
+			url:            "/swag/doc.json",
+			acceptEncoding: "gzip",
+			encoding:       "gzip",
+			contentType:    "application/json",
+		},
+		{
+			name:           "Images are not compressed",
+			url:            "/swag/favicon-16x16.png",
+			acceptEncoding: "gzip",
+			contentType:    "image/png",
+		},
+	}
+
+	etags := make(map[string]string)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+
+			// Compressing the bundle the first time takes a while
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != 200 {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 200)
+			}
+			if ct := resp.Header.Get("Content-Type"); ct != tt.contentType {
+				t.Fatalf(`Content-Type: got %s - expected %s`, ct, tt.contentType)
+			}
+			if ce := resp.Header.Get("Content-Encoding"); ce != tt.encoding {
+				t.Fatalf(`Content-Encoding: got %s - expected %s`, ce, tt.encoding)
+			}
+
+			if tt.body == nil {
+				return
+			}
+
+			if vary := resp.Header.Get("Vary"); !strings.Contains(vary, "Accept-Encoding") {
+				t.Fatalf(`Vary: got %s - expected Accept-Encoding`, vary)
+			}
+
+			etag := resp.Header.Get("ETag")
+			if other, ok := etags[tt.encoding]; ok && other != etag {
+				t.Fatalf("ETag of %q changed from %s to %s", tt.encoding, other, etag)
+			}
+			for encoding, other := range etags {
+				if encoding != tt.encoding && other == etag {
+					t.Fatalf("ETag %s is shared by %q and %q", etag, encoding, tt.encoding)
+				}
+			}
+			etags[tt.encoding] = etag
+
+			raw, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := decode[tt.encoding](raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(body, tt.body) {
+				t.Fatal("decoded body does not match the asset")
+			}
+		})
+	}
+}
+
+func Test_Compression_Transient(t *testing.T) {
+	body := []byte(strings.Repeat("Compressible ", 1000))
+	r := newTransientRendition(body, time.Now())
+
+	decode := map[string]func([]byte) ([]byte, error){
+		"gzip": func(b []byte) ([]byte, error) {
+			return fasthttp.AppendGunzipBytes(nil, b)
+		},
+		"br": func(b []byte) ([]byte, error) {
+			return fasthttp.AppendUnbrotliBytes(nil, b)
+		},
+		"zstd": func(b []byte) ([]byte, error) {
+			dec, err := zstd.NewReader(nil)
+			if err != nil {
+				return nil, err
+			}
+			defer dec.Close()
+			return dec.DecodeAll(b, nil)
+		},
+	}
+
+	for encoding, fn := range decode {
+		out := r.encoded(encoding)
+		if out == nil {
+			t.Fatalf("%s: body was not compressed", encoding)
+		}
+		decoded, err := fn(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decoded, body) {
+			t.Fatalf("%s: decoded body does not match", encoding)
+		}
+	}
+}
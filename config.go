@@ -2,6 +2,9 @@ package swagger
 
 import (
 	"html/template"
+	"io/fs"
+
+	"github.com/gofiber/fiber/v2"
 )
 
 // Config stores SwaggerUI configuration variables
@@ -10,18 +13,141 @@ type Config struct {
 	// default: ""
 	InstanceName string `json:"-"`
 
+	// Source of the API definition served under doc.json. Takes precedence over InstanceName.
+	// default: SwagSpec(InstanceName)
+	Spec Spec `json:"-"`
+
+	// Rewrites the host, basePath and schemes of Swagger 2.0 documents, or the servers of OpenAPI 3 documents,
+	// from the incoming request (Host, X-Forwarded-Host, X-Forwarded-Proto and X-Forwarded-Prefix headers),
+	// so that "Try it out" targets the host the documentation was loaded from.
+	// default: false
+	RewriteServers bool `json:"-"`
+
+	// Function to edit the served document on every request, called after RewriteServers is applied.
+	// default: nil
+	SpecTransformer func(c *fiber.Ctx, doc map[string]interface{}) error `json:"-"`
+
+	// Development mode serving the document from a file on disk, reloaded along with the open pages
+	// when the file changes, see HotReloadConfig. Spec and InstanceName are ignored.
+	// default: nil
+	HotReload *HotReloadConfig `json:"-"`
+
+	// Validates the documents when the handler is created and logs the findings as warnings, see Validate.
+	// Use NewE to get them as an error instead.
+	// default: false
+	ValidateSpec bool `json:"-"`
+
+	// Serves the differences between the routes of the application and the operations of the document
+	// under drift.json, see Diff. The report lists every route of the application, so it is meant for
+	// internal handlers only and cannot be combined with SpecFilters.
+	// default: false
+	RouteDrift bool `json:"-"`
+
+	// Converts Swagger 2.0 documents to this OpenAPI version, "3.0" or "3.1", served under openapi.json
+	// and openapi.yaml. The original document is still served under doc.json. OpenAPI 3 documents are
+	// served unchanged.
+	// default: "" -> no conversion
+	OutputVersion string `json:"-"`
+
+	// Filters selecting the operations served to each audience, see SpecFilter. The "" entry applies
+	// to requests without an audience and to audiences without an entry of their own. Without a ""
+	// entry, these requests get a document without any path.
+	// default: nil -> the whole document
+	SpecFilters map[string]SpecFilter `json:"-"`
+
+	// Function returning the audience of a request, e.g. from a header or the authenticated user.
+	// The documents then depend on the request, so CacheControl is made private.
+	// default: nil -> ""
+	Audience func(*fiber.Ctx) string `json:"-"`
+
+	// Function deciding whether a request may access the documentation, applied to index.html,
+	// the documents and the static assets alike. See BasicAuth, BearerAuth, APIKeyAuth and IPAllowlist.
+	// default: nil
+	Authorizer func(*fiber.Ctx) (bool, error) `json:"-"`
+
+	// Answers requests rejected by Authorizer with 404 Not Found instead of 401 Unauthorized,
+	// to hide the existence of the documentation.
+	// default: false
+	HideUnauthorized bool `json:"-"`
+
+	// Value of the Cache-Control header sent with index.html and the documents.
+	// Responses always carry ETag and Last-Modified validators, conditional requests are answered with 304.
+	// With an Audience, the value is always made private.
+	// default: "no-cache", or "private, no-cache" with an Authorizer
+	CacheControl string `json:"-"`
+
+	// Serves the documents and the text assets of Swagger UI compressed with brotli, zstd or gzip,
+	// depending on the Accept-Encoding header of the request. Compressed bodies are kept in memory.
+	// default: false
+	Compress bool `json:"-"`
+
+	// Swagger UI distribution served instead of the bundle of github.com/swaggo/files, e.g. the dist directory
+	// of the swagger-ui-dist package, to pin another version. Files it lacks are served from the embedded bundle.
+	// default: nil
+	Assets fs.FS `json:"-"`
+
+	// Base URL the page loads the Swagger UI scripts, stylesheet and icons from, e.g. a CDN such as
+	// "https://cdn.jsdelivr.net/npm/swagger-ui-dist@5.17.14". oauth2-redirect.html is still served by the middleware.
+	// default: "" -> served by the middleware
+	AssetsURL string `json:"-"`
+
+	// Documentation viewer rendered instead of Swagger UI, one of RedocConfig, RapiDocConfig,
+	// ScalarConfig or ElementsConfig. Only the first entry of URLs (or URLsPrimaryName) is shown.
+	// The options of this struct specific to Swagger UI are ignored.
+	// default: nil -> Swagger UI
+	Renderer Renderer `json:"-"`
+
+	// Makes the page reference same-origin assets only: the Google Fonts stylesheet is replaced by fonts
+	// bundled with the middleware and validatorUrl is forced to "none". A Renderer needs a same-origin ScriptURL.
+	// default: false
+	Offline bool `json:"-"`
+
+	// Sends a Content-Security-Policy header with index.html. Inline scripts and styles of the page carry
+	// a nonce generated per request, so that the policy needs no 'unsafe-inline' source.
+	// index.html is then sent with "Cache-Control: no-store".
+	// default: nil
+	ContentSecurityPolicy *CSPConfig `json:"-"`
+
+	// Sends the "Try it out" requests of Swagger UI to other origins through the middleware, under
+	// "_proxy/<scheme>/<host>/<path>", so that the API does not need to allow the documentation host with CORS.
+	// The handler must be registered for every method the operations use, e.g. with app.All.
+	// default: nil
+	Proxy *ProxyConfig `json:"-"`
+
+	// Template of index.html layered on top of the default one: a template source, a *template.Template
+	// or a TemplateFS. Templates made of definitions only override the "head", "before_ui" and "after_ui"
+	// blocks of the default page, a template with a body replaces the whole page. Templates are executed
+	// with IndexData and, besides the built-in functions, may call "nonce". Ignored with a Renderer.
+	// default: nil
+	IndexTemplate interface{} `json:"-"`
+
 	// Title pointing to title of HTML page.
 	// default: "Swagger UI"
 	Title string `json:"-"`
 
 	// URL to fetch external configuration document from.
-	// default: ""
+	// default: "" -> "swagger-config.json" with ExternalConfig
 	ConfigURL string `json:"configUrl,omitempty"`
 
+	// Makes index.html load the configuration from ConfigURL instead of inlining it. The middleware
+	// serves the JSON options of this struct under swagger-config.json either way.
+	// Options holding JavaScript, such as Plugins or RequestInterceptor, are still inlined.
+	// default: false
+	ExternalConfig bool `json:"-"`
+
 	// The URL pointing to API definition (normally swagger.json or swagger.yaml).
 	// default: "doc.json"
 	URL string `json:"url,omitempty"`
 
+	// An array of API definitions rendered as a selector in the top bar. Entries referencing a swag instance
+	// are served by the middleware under "<InstanceName>/doc.json". When set, URL is ignored.
+	// default: nil
+	URLs []SpecURL `json:"urls,omitempty"`
+
+	// Name of the entry of URLs that is selected when Swagger UI loads.
+	// default: "" -> first entry of URLs
+	URLsPrimaryName string `json:"urls.primaryName,omitempty"`
+
 	// Enables overriding configuration parameters via URL search params.
 	// default: false
 	QueryConfigEnabled bool `json:"queryConfigEnabled,omitempty"`
@@ -185,6 +311,26 @@ type Config struct {
 	CustomScript template.JS `json:"-"`
 }
 
+// SpecURL is an entry of the API definition selector.
+type SpecURL struct {
+	// Name displayed in the selector.
+	Name string `json:"name"`
+
+	// Name of the swag instance served by the middleware for this entry.
+	// The entry is served under "<InstanceName>/doc.json", also when Spec is set.
+	// default: ""
+	InstanceName string `json:"-"`
+
+	// Source of the API definition, replaces the lookup of InstanceName in the swag registry.
+	// InstanceName is still required, it names the path the entry is served under.
+	// default: nil
+	Spec Spec `json:"-"`
+
+	// URL of an externally hosted API definition. Takes precedence over InstanceName.
+	// default: ""
+	URL string `json:"url"`
+}
+
 type FilterConfig struct {
 	Enabled    bool
 	Expression string
@@ -255,8 +401,9 @@ type OAuthConfig struct {
 
 var (
 	ConfigDefault = Config{
-		Title:  "Swagger UI",
-		Layout: "StandaloneLayout",
+		CacheControl: "no-cache",
+		Title:        "Swagger UI",
+		Layout:       "StandaloneLayout",
 		Plugins: []template.JS{
 			template.JS("SwaggerUIBundle.plugins.DownloadUrl"),
 		},
@@ -281,12 +428,27 @@ var (
 func configDefault(config ...Config) Config {
 	// Return default config if nothing provided
 	if len(config) < 1 {
-		return ConfigDefault
+		cfg := ConfigDefault
+		cfg.Spec = SwagSpec(cfg.InstanceName)
+		return cfg
 	}
 
 	// Override default config
 	cfg := config[0]
 
+	if cfg.CacheControl == "" {
+		cfg.CacheControl = ConfigDefault.CacheControl
+		// Shared caches must not hand protected documents to other clients
+		if cfg.Authorizer != nil {
+			cfg.CacheControl = "private, " + cfg.CacheControl
+		}
+	}
+
+	// Shared caches must not hand the document filtered for one audience to another
+	if cfg.Audience != nil {
+		cfg.CacheControl = privateCacheControl(cfg.CacheControl)
+	}
+
 	if cfg.Title == "" {
 		cfg.Title = ConfigDefault.Title
 	}
@@ -315,5 +477,13 @@ func configDefault(config ...Config) Config {
 		cfg.SyntaxHighlight = ConfigDefault.SyntaxHighlight
 	}
 
+	if cfg.Spec == nil {
+		cfg.Spec = SwagSpec(cfg.InstanceName)
+	}
+
+	if cfg.Offline {
+		cfg.ValidatorUrl = "none"
+	}
+
 	return cfg
 }
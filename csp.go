@@ -0,0 +1,172 @@
+package swagger
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// CSPConfig configures the Content-Security-Policy sent with index.html.
+// Inline scripts and styles of the page carry a nonce generated per request.
+type CSPConfig struct {
+	// Directives replacing the ones of the default policy, e.g. {"connect-src": "'self' https://api.example.com"}.
+	// The nonce of the request is available as the "{nonce}" placeholder. An empty value removes the directive.
+	// default: nil
+	Directives map[string]string
+
+	// Additional sources appended to the directives of the policy, e.g. {"img-src": "https://cdn.example.com"}.
+	// default: nil
+	ExtraSources map[string]string
+
+	// Sends the policy in a Content-Security-Policy-Report-Only header.
+	// default: false
+	ReportOnly bool
+}
+
+const cspNonce = "{nonce}"
+
+// Order of the directives in the header
+var cspDirectives = []string{
+	"default-src", "script-src", "style-src", "font-src", "img-src", "connect-src", "object-src", "base-uri", "frame-ancestors",
+}
+
+// header returns the name of the header the policy is sent in
+func (cc *CSPConfig) header() string {
+	if cc.ReportOnly {
+		return "Content-Security-Policy-Report-Only"
+	}
+	return "Content-Security-Policy"
+}
+
+// policy builds the policy for cfg, the nonce is left as the "{nonce}" placeholder
+func (cc *CSPConfig) policy(cfg *Config) string {
+	directives := map[string][]string{
+		"default-src":     {"'self'"},
+		"script-src":      {"'self'", "'nonce-" + cspNonce + "'"},
+		"style-src":       {"'self'", "'nonce-" + cspNonce + "'"},
+		"font-src":        {"'self'"},
+		"img-src":         {"'self'", "data:"},
+		"connect-src":     {"'self'"},
+		"object-src":      {"'none'"},
+		"base-uri":        {"'self'"},
+		"frame-ancestors": {"'self'"},
+	}
+
+	add := func(directive, rawURL string) {
+		if origin := originOf(rawURL); origin != "" {
+			directives[directive] = appendUnique(directives[directive], origin)
+		}
+	}
+
+	if cfg.Renderer != nil {
+		script, style := cfg.Renderer.assets()
+		add("script-src", script)
+		add("style-src", style)
+	} else {
+		// Swagger UI loaded from a CDN
+		add("script-src", cfg.AssetsURL)
+		add("style-src", cfg.AssetsURL)
+		add("img-src", cfg.AssetsURL)
+	}
+	if cfg.Renderer == nil && !cfg.Offline {
+		directives["style-src"] = append(directives["style-src"], "https://fonts.googleapis.com")
+		directives["font-src"] = append(directives["font-src"], "https://fonts.gstatic.com")
+		if cfg.ValidatorUrl == "" {
+			directives["img-src"] = append(directives["img-src"], "https://validator.swagger.io")
+		}
+	}
+
+	// Definitions and configuration hosted elsewhere are fetched by the page
+	add("connect-src", cfg.URL)
+	add("connect-src", cfg.ConfigURL)
+	for _, u := range cfg.URLs {
+		add("connect-src", u.URL)
+	}
+
+	for directive, sources := range cc.ExtraSources {
+		for _, source := range strings.Fields(sources) {
+			directives[directive] = appendUnique(directives[directive], source)
+		}
+	}
+
+	// Well-known directives first, then the others in alphabetical order
+	names := append([]string(nil), cspDirectives...)
+	var others []string
+	for directive := range directives {
+		if !containsString(cspDirectives, directive) {
+			others = append(others, directive)
+		}
+	}
+	for directive := range cc.Directives {
+		if _, ok := directives[directive]; !ok {
+			others = append(others, directive)
+		}
+	}
+	sort.Strings(others)
+	names = append(names, others...)
+
+	parts := make([]string, 0, len(names))
+	for _, directive := range names {
+		value := strings.Join(directives[directive], " ")
+		if override, ok := cc.Directives[directive]; ok {
+			value = override
+		}
+		if value == "" {
+			continue
+		}
+		parts = append(parts, directive+" "+value)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// originOf returns the origin of an absolute url, or an empty string for same-origin urls
+func originOf(rawURL string) string {
+	if !isExternalURL(rawURL) {
+		return ""
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	if u.Scheme == "" {
+		return u.Host
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// newNonce returns a random nonce
+func newNonce() string {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// newPlaceholder returns a random token standing for the nonce in the cached page
+func newPlaceholder() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return "nonce-" + hex.EncodeToString(b)
+}
+
+func appendUnique(values []string, value string) []string {
+	if containsString(values, value) {
+		return values
+	}
+	return append(values, value)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
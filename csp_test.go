@@ -0,0 +1,138 @@
+package swagger
+
+import (
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	inlineTagRe = regexp.MustCompile(`<(script|style)([^>]*)>`)
+	nonceRe     = regexp.MustCompile(`'nonce-([^']+)'`)
+)
+
+func Test_Content_Security_Policy(t *testing.T) {
+	get := func(t *testing.T, app *fiber.App) (*http.Response, string) {
+		t.Helper()
+
+		req, err := http.NewRequest(http.MethodGet, "/swag/index.html", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp, string(body)
+	}
+
+	t.Run("Should attach the nonce to every inline block", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/swag/*", New(Config{
+			Spec:                  BytesSpec([]byte(`{"swagger":"2.0"}`)),
+			CustomStyle:           "body { color: red; }",
+			CustomScript:          "console.log('custom')",
+			ContentSecurityPolicy: &CSPConfig{},
+		}))
+
+		var previous string
+		for i := 0; i < 2; i++ {
+			resp, body := get(t, app)
+
+			policy := resp.Header.Get("Content-Security-Policy")
+			match := nonceRe.FindStringSubmatch(policy)
+			if match == nil {
+				t.Fatalf("policy has no nonce: %s", policy)
+			}
+			nonce := match[1]
+			if nonce == previous {
+				t.Fatal("nonce is reused across requests")
+			}
+			previous = nonce
+
+			expected := "default-src 'self'; script-src 'self' 'nonce-" + nonce + "'; style-src 'self' 'nonce-" + nonce + "' https://fonts.googleapis.com; " +
+				"font-src 'self' https://fonts.gstatic.com; img-src 'self' data: https://validator.swagger.io; connect-src 'self'; " +
+				"object-src 'none'; base-uri 'self'; frame-ancestors 'self'"
+			if policy != expected {
+				t.Fatalf("Content-Security-Policy: got %s - expected %s", policy, expected)
+			}
+
+			if cc := resp.Header.Get("Cache-Control"); cc != "no-store" {
+				t.Fatalf(`Cache-Control: got %s - expected %s`, cc, "no-store")
+			}
+
+			tags := inlineTagRe.FindAllStringSubmatch(body, -1)
+			if len(tags) != 6 {
+				t.Fatalf("expected 6 script and style tags, got %d", len(tags))
+			}
+			for _, tag := range tags {
+				if strings.Contains(tag[2], "src=") {
+					continue
+				}
+				if tag[2] != ` nonce="`+nonce+`"` {
+					t.Fatalf("inline block without the nonce: %s", tag[0])
+				}
+			}
+		}
+	})
+
+	t.Run("Should extend and override the policy", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/swag/*", New(Config{
+			URL:      "https://petstore.swagger.io/v2/swagger.json",
+			Renderer: RedocConfig{},
+			ContentSecurityPolicy: &CSPConfig{
+				Directives: map[string]string{
+					"frame-ancestors": "'none'",
+					"base-uri":        "",
+					"report-uri":      "/csp-report",
+				},
+				ExtraSources: map[string]string{"img-src": "https://cdn.example.com"},
+				ReportOnly:   true,
+			},
+		}))
+
+		resp, body := get(t, app)
+
+		if resp.Header.Get("Content-Security-Policy") != "" {
+			t.Fatal("enforced policy sent in report-only mode")
+		}
+		policy := resp.Header.Get("Content-Security-Policy-Report-Only")
+		nonce := nonceRe.FindStringSubmatch(policy)[1]
+
+		expected := "default-src 'self'; script-src 'self' 'nonce-" + nonce + "' https://cdn.redoc.ly; style-src 'self' 'nonce-" + nonce + "'; " +
+			"font-src 'self'; img-src 'self' data: https://cdn.example.com; connect-src 'self' https://petstore.swagger.io; " +
+			"object-src 'none'; frame-ancestors 'none'; report-uri /csp-report"
+		if policy != expected {
+			t.Fatalf("Content-Security-Policy-Report-Only: got %s - expected %s", policy, expected)
+		}
+
+		if !strings.Contains(body, `<script nonce="`+nonce+`">`) {
+			t.Fatal("inline script without the nonce")
+		}
+	})
+
+	t.Run("Should not add nonces without a policy", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/swag/*", New(Config{Spec: BytesSpec([]byte(`{"swagger":"2.0"}`))}))
+
+		resp, body := get(t, app)
+
+		if resp.Header.Get("Content-Security-Policy") != "" {
+			t.Fatal("unexpected Content-Security-Policy header")
+		}
+		if strings.Contains(body, "nonce") {
+			t.Fatal("unexpected nonce attribute")
+		}
+	})
+}
@@ -0,0 +1,151 @@
+package swagger
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const defaultDriftURL = "drift.json"
+
+// Route is an HTTP method and path of the application or of the document.
+type Route struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// RouteDrift lists the differences between the routes of an application and the operations of its document.
+type RouteDrift struct {
+	// Routes of the application missing from the document, with their Fiber path.
+	Undocumented []Route `json:"undocumented"`
+
+	// Operations of the document without a route, with their path prefixed by the base path of the document.
+	Missing []Route `json:"missing"`
+}
+
+// Empty reports whether the routes and the document agree.
+func (d *RouteDrift) Empty() bool {
+	return len(d.Undocumented) == 0 && len(d.Missing) == 0
+}
+
+// Diff compares the routes of app with the operations of the swag instance named instanceName.
+// Parameters match regardless of their name, ":id" and "{id}" alike, and the paths of the document
+// are prefixed by its basePath, or the path of its first server. Wildcard routes, such as the one
+// of this middleware, and the HEAD routes Fiber adds for GET routes are ignored.
+func Diff(app *fiber.App, instanceName string) (*RouteDrift, error) {
+	return diff(context.Background(), app, SwagSpec(instanceName))
+}
+
+// Parameters of the document, those of the routes are matched by routeParamRe
+var specParamRe = regexp.MustCompile(`{[^}/]+}`)
+
+func diff(ctx context.Context, app *fiber.App, spec Spec) (*RouteDrift, error) {
+	doc, isYAML, err := readSpec(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	if isYAML {
+		if doc, err = yamlToJSON(doc); err != nil {
+			return nil, err
+		}
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(doc, &m); err != nil {
+		return nil, err
+	}
+
+	cfg := app.Config()
+	normalize := func(p string, params *regexp.Regexp) string {
+		p = params.ReplaceAllString(p, "{}")
+		if !cfg.StrictRouting && len(p) > 1 {
+			p = strings.TrimSuffix(p, "/")
+		}
+		if !cfg.CaseSensitive {
+			p = strings.ToLower(p)
+		}
+		return p
+	}
+
+	documented := make(map[Route]Route)
+	paths, _ := m["paths"].(map[string]interface{})
+	base := basePath(m)
+	for p, raw := range paths {
+		item, _ := raw.(map[string]interface{})
+		full := joinPath(base, p)
+		for _, method := range operationMethods {
+			if _, ok := item[method]; ok {
+				route := Route{Method: strings.ToUpper(method), Path: full}
+				documented[Route{Method: route.Method, Path: normalize(full, specParamRe)}] = route
+			}
+		}
+	}
+
+	drift := &RouteDrift{Undocumented: []Route{}, Missing: []Route{}}
+	routed := make(map[Route]bool)
+	gets := make(map[string]bool)
+	routes := app.GetRoutes(true)
+	for _, r := range routes {
+		if r.Method == fiber.MethodGet {
+			gets[r.Path] = true
+		}
+	}
+	for _, r := range routes {
+		if strings.ContainsAny(r.Path, "*+") || !containsString(operationMethods, strings.ToLower(r.Method)) {
+			continue
+		}
+		key := Route{Method: r.Method, Path: normalize(r.Path, routeParamRe)}
+		if routed[key] {
+			continue
+		}
+		routed[key] = true
+		if _, ok := documented[key]; ok {
+			continue
+		}
+		if r.Method == fiber.MethodHead && gets[r.Path] {
+			continue
+		}
+		drift.Undocumented = append(drift.Undocumented, Route{Method: r.Method, Path: r.Path})
+	}
+
+	for key, route := range documented {
+		if !routed[key] {
+			drift.Missing = append(drift.Missing, route)
+		}
+	}
+
+	sortRoutes(drift.Undocumented)
+	sortRoutes(drift.Missing)
+	return drift, nil
+}
+
+// basePath returns the path prefix of the operations of a document
+func basePath(doc map[string]interface{}) string {
+	if base, ok := doc["basePath"].(string); ok {
+		return base
+	}
+	servers, _ := doc["servers"].([]interface{})
+	if len(servers) == 0 {
+		return "/"
+	}
+	server, _ := servers[0].(map[string]interface{})
+	raw, _ := server["url"].(string)
+	u, err := url.Parse(raw)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
+func sortRoutes(routes []Route) {
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+}
@@ -0,0 +1,113 @@
+package swagger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_Diff(t *testing.T) {
+	handler := func(c *fiber.Ctx) error { return nil }
+
+	tests := []struct {
+		name   string
+		doc    string
+		routes func(app *fiber.App)
+		drift  RouteDrift
+	}{
+		{
+			name: "Swagger 2.0 document",
+			doc: `{"swagger":"2.0","basePath":"/v2","paths":{
+				"/users":{"get":{},"post":{}},
+				"/users/{userId}":{"get":{},"delete":{}},
+				"/orders":{"get":{}}}}`,
+			routes: func(app *fiber.App) {
+				v2 := app.Group("/v2")
+				v2.Get("/users", handler)
+				v2.Post("/users/", handler)
+				v2.Get("/users/:id<int>", handler)
+				v2.Patch("/users/:id", handler)
+				app.Get("/health", handler)
+				app.Get("/swagger/*", handler)
+			},
+			drift: RouteDrift{
+				Undocumented: []Route{{Method: "GET", Path: "/health"}, {Method: "PATCH", Path: "/v2/users/:id"}},
+				Missing:      []Route{{Method: "GET", Path: "/v2/orders"}, {Method: "DELETE", Path: "/v2/users/{userId}"}},
+			},
+		},
+		{
+			name: "OpenAPI 3 document",
+			doc: "openapi: 3.0.3\nservers:\n  - url: https://api.example.com/api\npaths:\n  /items/{id}:\n    put: {}\n" +
+				"  /flights/{from}-{to}:\n    get: {}\n  /files/{name}.{ext}:\n    get: {}\n",
+			routes: func(app *fiber.App) {
+				app.Put("/api/items/:itemId", handler)
+				app.Get("/api/flights/:from-:to", handler)
+				app.Get("/api/files/:name.:ext", handler)
+			},
+			drift: RouteDrift{Undocumented: []Route{}, Missing: []Route{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			tt.routes(app)
+
+			drift, err := diff(context.Background(), app, BytesSpec([]byte(tt.doc)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(*drift, tt.drift) {
+				t.Fatalf("Drift: got %+v - expected %+v", *drift, tt.drift)
+			}
+		})
+	}
+}
+
+func Test_Swagger_Route_Drift(t *testing.T) {
+	app := fiber.New()
+	app.Get("/swag/*", New(Config{
+		Spec:       BytesSpec([]byte(`{"swagger":"2.0","paths":{"/users":{"get":{}}}}`)),
+		RouteDrift: true,
+	}))
+	app.Get("/users", func(c *fiber.Ctx) error { return nil })
+	app.Get("/orders", func(c *fiber.Ctx) error { return nil })
+
+	req, err := http.NewRequest(http.MethodGet, "/swag/drift.json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 200)
+	}
+
+	var drift RouteDrift
+	if err := json.NewDecoder(resp.Body).Decode(&drift); err != nil {
+		t.Fatal(err)
+	}
+	expected := RouteDrift{Undocumented: []Route{{Method: "GET", Path: "/orders"}}, Missing: []Route{}}
+	if !reflect.DeepEqual(drift, expected) {
+		t.Fatalf("Drift: got %+v - expected %+v", drift, expected)
+	}
+
+	t.Run("Should refuse the report with filtered documents", func(t *testing.T) {
+		_, err := NewE(Config{
+			Spec:        BytesSpec([]byte(`{"swagger":"2.0","info":{"title":"API","version":"1"},"paths":{}}`)),
+			RouteDrift:  true,
+			SpecFilters: map[string]SpecFilter{"partner": {IncludeTags: []string{"orders"}}},
+			Audience:    func(c *fiber.Ctx) string { return c.Get("X-Audience") },
+		})
+		if err == nil {
+			t.Fatal("expected a configuration error")
+		}
+	})
+}
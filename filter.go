@@ -0,0 +1,359 @@
+package swagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SpecFilter selects the operations of the served document.
+// Definitions no longer referenced once operations are dropped are removed as well.
+type SpecFilter struct {
+	// Keeps only the operations with at least one of these tags.
+	// default: nil -> all operations
+	IncludeTags []string
+
+	// Drops the operations with any of these tags.
+	// default: nil
+	ExcludeTags []string
+
+	// Keeps only the paths matching one of these patterns. "*" matches within a path segment,
+	// "**" across segments, e.g. "/users/**".
+	// default: nil -> all paths
+	IncludePaths []string
+
+	// Drops the paths matching any of these patterns.
+	// default: nil
+	ExcludePaths []string
+
+	// Drops the operations using one of these HTTP methods, e.g. "DELETE".
+	// default: nil
+	ExcludeMethods []string
+
+	// Drops the paths and operations marked with "x-internal: true".
+	// default: false
+	HideInternal bool
+
+	// Keeps only the paths and operations whose "x-visibility" extension is one of these values.
+	// Operations without the extension are kept.
+	// default: nil -> any visibility
+	Visibility []string
+}
+
+// HTTP methods of path items, as used by both Swagger 2.0 and OpenAPI 3
+var operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// specFilter is a SpecFilter with its patterns compiled
+type specFilter struct {
+	SpecFilter
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+	// Drops every path, for audiences without a filter
+	denyAll bool
+}
+
+func newSpecFilter(f SpecFilter) *specFilter {
+	sf := &specFilter{SpecFilter: f}
+	for _, pattern := range f.IncludePaths {
+		sf.include = append(sf.include, compileGlob(pattern))
+	}
+	for _, pattern := range f.ExcludePaths {
+		sf.exclude = append(sf.exclude, compileGlob(pattern))
+	}
+	return sf
+}
+
+// compileGlob converts a path pattern to a regular expression
+func compileGlob(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteByte('^')
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			sb.WriteString("[^/]*")
+		case pattern[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	sb.WriteByte('$')
+	return regexp.MustCompile(sb.String())
+}
+
+// audienceFilters resolves the filter applying to a request
+type audienceFilters struct {
+	filters  map[string]*specFilter
+	audience func(*fiber.Ctx) string
+	// Filter of the audiences without an entry when there is no "" entry either
+	fallback *specFilter
+}
+
+func newAudienceFilters(cfg *Config) *audienceFilters {
+	if len(cfg.SpecFilters) == 0 {
+		return nil
+	}
+	af := &audienceFilters{filters: make(map[string]*specFilter, len(cfg.SpecFilters)), audience: cfg.Audience}
+	for audience, f := range cfg.SpecFilters {
+		af.filters[audience] = newSpecFilter(f)
+	}
+	af.fallback = af.filters[""]
+	if af.fallback == nil {
+		// An unexpected audience must not see the whole document
+		af.fallback = &specFilter{denyAll: true}
+	}
+	return af
+}
+
+// lookup returns the filter of the audience of the request along with its key, or nil if the document is not filtered
+func (af *audienceFilters) lookup(c *fiber.Ctx) (*specFilter, string) {
+	if af == nil {
+		return nil, ""
+	}
+	audience := ""
+	if af.audience != nil {
+		audience = af.audience(c)
+	}
+	if f, ok := af.filters[audience]; ok {
+		return f, audience
+	}
+	// Unknown audiences get the default filter, or an empty document without one. Both only
+	// happen under the "" key, which is never a filter of its own in the latter case.
+	return af.fallback, ""
+}
+
+// apply filters a JSON document
+func (sf *specFilter) apply(doc []byte) ([]byte, error) {
+	var m map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("invalid json document: %w", err)
+	}
+
+	before := referencedComponents(m)
+
+	paths, _ := m["paths"].(map[string]interface{})
+	usedTags := make(map[string]bool)
+	for p, rawItem := range paths {
+		item, ok := rawItem.(map[string]interface{})
+		if !ok || !sf.keepPath(p, item) {
+			delete(paths, p)
+			continue
+		}
+
+		operations := 0
+		for _, method := range operationMethods {
+			op, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if !sf.keepOperation(method, op) {
+				delete(item, method)
+				continue
+			}
+			operations++
+			for _, tag := range stringSlice(op["tags"]) {
+				usedTags[tag] = true
+			}
+		}
+		if operations == 0 {
+			delete(paths, p)
+		}
+	}
+
+	// Tags of dropped operations disappear from the top-level tag list
+	if tags, ok := m["tags"].([]interface{}); ok {
+		kept := make([]interface{}, 0, len(tags))
+		for _, t := range tags {
+			if tag, ok := t.(map[string]interface{}); ok {
+				if name, _ := tag["name"].(string); !usedTags[name] {
+					continue
+				}
+			}
+			kept = append(kept, t)
+		}
+		m["tags"] = kept
+	}
+
+	pruneComponents(m, before)
+
+	return marshalJSON(m)
+}
+
+func (sf *specFilter) keepPath(p string, item map[string]interface{}) bool {
+	if sf.denyAll {
+		return false
+	}
+	if len(sf.include) > 0 && !matchAnyRegexp(sf.include, p) {
+		return false
+	}
+	if matchAnyRegexp(sf.exclude, p) {
+		return false
+	}
+	return sf.visible(item)
+}
+
+func (sf *specFilter) keepOperation(method string, op map[string]interface{}) bool {
+	for _, m := range sf.ExcludeMethods {
+		if strings.EqualFold(m, method) {
+			return false
+		}
+	}
+
+	tags := stringSlice(op["tags"])
+	if len(sf.IncludeTags) > 0 && !intersects(tags, sf.IncludeTags) {
+		return false
+	}
+	if intersects(tags, sf.ExcludeTags) {
+		return false
+	}
+
+	return sf.visible(op)
+}
+
+// visible evaluates the visibility extensions of a path item or an operation
+func (sf *specFilter) visible(node map[string]interface{}) bool {
+	if internal, _ := node["x-internal"].(bool); internal && sf.HideInternal {
+		return false
+	}
+	if visibility, ok := node["x-visibility"].(string); ok && len(sf.Visibility) > 0 {
+		return containsString(sf.Visibility, visibility)
+	}
+	return true
+}
+
+// componentSections returns the sections of reusable objects of the document by their $ref prefix
+func componentSections(doc map[string]interface{}) map[string]map[string]interface{} {
+	sections := make(map[string]map[string]interface{})
+	if _, ok := doc["openapi"]; ok {
+		components, _ := doc["components"].(map[string]interface{})
+		for name, section := range components {
+			// Security schemes are referenced by name from security requirements
+			if s, ok := section.(map[string]interface{}); ok && name != "securitySchemes" {
+				sections["#/components/"+name+"/"] = s
+			}
+		}
+		return sections
+	}
+	for _, name := range []string{"definitions", "parameters", "responses"} {
+		if s, ok := doc[name].(map[string]interface{}); ok {
+			sections["#/"+name+"/"] = s
+		}
+	}
+	return sections
+}
+
+// referencedComponents returns the $refs to reusable objects reachable from the paths and other
+// parts of the document, following references between reusable objects
+func referencedComponents(doc map[string]interface{}) map[string]bool {
+	sections := componentSections(doc)
+	refs := make(map[string]bool)
+	var queue []string
+
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			for k, child := range t {
+				if ref, ok := child.(string); ok && k == "$ref" {
+					if !refs[ref] {
+						refs[ref] = true
+						queue = append(queue, ref)
+					}
+					continue
+				}
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range t {
+				walk(child)
+			}
+		}
+	}
+
+	for k, v := range doc {
+		switch k {
+		case "definitions", "parameters", "responses":
+			if _, ok := doc["openapi"]; !ok {
+				continue
+			}
+		case "components":
+			if components, ok := v.(map[string]interface{}); ok {
+				// Only security schemes are roots, the other components are reached through references
+				walk(components["securitySchemes"])
+				continue
+			}
+		}
+		walk(v)
+	}
+
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		for prefix, section := range sections {
+			if strings.HasPrefix(ref, prefix) {
+				walk(section[unescapePointer(strings.TrimPrefix(ref, prefix))])
+			}
+		}
+	}
+
+	return refs
+}
+
+// pruneComponents removes the reusable objects which were referenced before filtering but no longer are
+func pruneComponents(doc map[string]interface{}, before map[string]bool) {
+	after := referencedComponents(doc)
+	for prefix, section := range componentSections(doc) {
+		for name := range section {
+			ref := prefix + escapePointer(name)
+			if before[ref] && !after[ref] {
+				delete(section, name)
+			}
+		}
+	}
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+func unescapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+}
+
+func stringSlice(v interface{}) []string {
+	values, _ := v.([]interface{})
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func intersects(a, b []string) bool {
+	for _, s := range a {
+		if containsString(b, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAnyRegexp(res []*regexp.Regexp, s string) bool {
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,214 @@
+package swagger
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const filterSwaggerDoc = `{
+	"swagger": "2.0",
+	"tags": [{"name": "users"}, {"name": "admin"}],
+	"paths": {
+		"/users": {
+			"get": {"tags": ["users"], "responses": {"200": {"schema": {"$ref": "#/definitions/User"}}}},
+			"delete": {"tags": ["users"], "responses": {"204": {}}}
+		},
+		"/admin/stats": {
+			"get": {"tags": ["admin"], "x-visibility": "internal", "responses": {"200": {"schema": {"$ref": "#/definitions/Stats"}}}}
+		},
+		"/health": {
+			"x-internal": true,
+			"get": {"responses": {"200": {}}}
+		}
+	},
+	"definitions": {
+		"User": {"properties": {"address": {"$ref": "#/definitions/Address"}}},
+		"Address": {"type": "object"},
+		"Stats": {"properties": {"counter": {"$ref": "#/definitions/Counter"}}},
+		"Counter": {"type": "integer"},
+		"Unused": {"type": "string"}
+	}
+}`
+
+func Test_Spec_Filter(t *testing.T) {
+	tests := []struct {
+		name   string
+		doc    string
+		filter SpecFilter
+		body   string
+	}{
+		{
+			name:   "Include tags",
+			doc:    filterSwaggerDoc,
+			filter: SpecFilter{IncludeTags: []string{"users"}},
+			body: `{"definitions":{"Address":{"type":"object"},"Unused":{"type":"string"},"User":{"properties":{"address":{"$ref":"#/definitions/Address"}}}},` +
+				`"paths":{"/users":{"delete":{"responses":{"204":{}},"tags":["users"]},"get":{"responses":{"200":{"schema":{"$ref":"#/definitions/User"}}},"tags":["users"]}}},` +
+				`"swagger":"2.0","tags":[{"name":"users"}]}`,
+		},
+		{
+			name:   "Exclude paths and methods",
+			doc:    filterSwaggerDoc,
+			filter: SpecFilter{ExcludePaths: []string{"/admin/**", "/health"}, ExcludeMethods: []string{"DELETE"}},
+			body: `{"definitions":{"Address":{"type":"object"},"Unused":{"type":"string"},"User":{"properties":{"address":{"$ref":"#/definitions/Address"}}}},` +
+				`"paths":{"/users":{"get":{"responses":{"200":{"schema":{"$ref":"#/definitions/User"}}},"tags":["users"]}}},` +
+				`"swagger":"2.0","tags":[{"name":"users"}]}`,
+		},
+		{
+			name:   "Visibility extensions",
+			doc:    filterSwaggerDoc,
+			filter: SpecFilter{IncludePaths: []string{"/*/*", "/health"}, HideInternal: true, Visibility: []string{"public"}},
+			body:   `{"definitions":{"Unused":{"type":"string"}},"paths":{},"swagger":"2.0","tags":[]}`,
+		},
+		{
+			name: "OpenAPI 3 components",
+			doc: `{"openapi":"3.0.3","paths":{
+				"/a":{"get":{"tags":["a"],"responses":{"200":{"$ref":"#/components/responses/A"}}}},
+				"/b":{"get":{"tags":["b"],"responses":{"200":{"$ref":"#/components/responses/B"}}}}},
+				"components":{"responses":{"A":{"description":"a"},"B":{"description":"b","content":{"application/json":{"schema":{"$ref":"#/components/schemas/B~1v1"}}}}},
+				"schemas":{"B/v1":{"type":"object"}},"securitySchemes":{"key":{"type":"apiKey"}}}}`,
+			filter: SpecFilter{ExcludeTags: []string{"b"}},
+			body: `{"components":{"responses":{"A":{"description":"a"}},"schemas":{},"securitySchemes":{"key":{"type":"apiKey"}}},` +
+				`"openapi":"3.0.3","paths":{"/a":{"get":{"responses":{"200":{"$ref":"#/components/responses/A"}},"tags":["a"]}}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/swag/*", New(Config{
+				Spec:        BytesSpec([]byte(tt.doc)),
+				SpecFilters: map[string]SpecFilter{"": tt.filter},
+			}))
+
+			req, err := http.NewRequest(http.MethodGet, "/swag/doc.json", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(body) != tt.body {
+				t.Fatalf("Body: got %s - expected %s", body, tt.body)
+			}
+		})
+	}
+}
+
+func Test_Spec_Filter_Audience(t *testing.T) {
+	app := fiber.New()
+	app.Get("/swag/*", New(Config{
+		Spec: BytesSpec([]byte(filterSwaggerDoc)),
+		SpecFilters: map[string]SpecFilter{
+			"":      {ExcludeTags: []string{"admin"}, HideInternal: true},
+			"staff": {},
+		},
+		Audience: func(c *fiber.Ctx) string {
+			return c.Get("X-Audience")
+		},
+	}))
+
+	tests := []struct {
+		audience string
+		url      string
+		body     string
+	}{
+		{
+			audience: "staff",
+			url:      "/swag/doc.yaml",
+			body:     "paths:\n  /admin/stats:\n",
+		},
+		{
+			audience: "unknown",
+			url:      "/swag/doc.json",
+			body:     `"paths":{"/users":`,
+		},
+		{
+			audience: "",
+			url:      "/swag/doc.json",
+			body:     `"paths":{"/users":`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.audience, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("X-Audience", tt.audience)
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cc := resp.Header.Get("Cache-Control"); cc != "private, no-cache" {
+				t.Fatalf(`Cache-Control: got %s - expected %s`, cc, "private, no-cache")
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(body), tt.body) {
+				t.Fatalf("Body: got %s - expected to contain %s", body, tt.body)
+			}
+		})
+	}
+}
+
+func Test_Spec_Filter_Unknown_Audience(t *testing.T) {
+	app := fiber.New()
+	app.Get("/swag/*", New(Config{
+		Spec:         BytesSpec([]byte(filterSwaggerDoc)),
+		SpecFilters:  map[string]SpecFilter{"staff": {}},
+		Audience:     func(c *fiber.Ctx) string { return c.Get("X-Audience") },
+		CacheControl: "public, max-age=60",
+	}))
+
+	tests := []struct {
+		audience     string
+		body         string
+		cacheControl string
+	}{
+		{audience: "staff", body: `"paths":{"/admin/stats":`, cacheControl: "private, max-age=60"},
+		{audience: "partner", body: `"paths":{}`, cacheControl: "private, max-age=60"},
+		{audience: "", body: `"paths":{}`, cacheControl: "private, max-age=60"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.audience, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/swag/doc.json", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("X-Audience", tt.audience)
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cc := resp.Header.Get("Cache-Control"); cc != tt.cacheControl {
+				t.Fatalf(`Cache-Control: got %s - expected %s`, cc, tt.cacheControl)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(body), tt.body) {
+				t.Fatalf("Body: got %s - expected to contain %s", body, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package swagger
+
+import (
+	"html/template"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+	"text/template/parse"
+	"time"
+)
+
+// Cache-Control of the assets served under a fingerprinted name, their content never changes
+const immutableCacheControl = "max-age=31536000, immutable"
+
+// Length of the content hash in fingerprinted names
+const fingerprintLen = 12
+
+// Names such as "swagger-ui-bundle.0123456789ab.js"
+var fingerprintRe = regexp.MustCompile(`^(.+)\.([0-9a-f]{12})(\.[A-Za-z0-9]+)$`)
+
+// fingerprints serves the static assets under names carrying a hash of their content, so that
+// browsers may cache them for good and fetch new ones as soon as the page references another version.
+// Only the assets known when the handler is created are fingerprinted, the set never grows afterwards.
+type fingerprints struct {
+	// Renditions by original name, missing files are left out
+	files map[string]*rendition
+}
+
+// newFingerprints reads the assets names from fsys and computes their fingerprints
+func newFingerprints(fsys fs.FS, names []string) *fingerprints {
+	fp := &fingerprints{files: make(map[string]*rendition, len(names))}
+	modTime := time.Now().UTC().Truncate(time.Second)
+	for _, name := range names {
+		if body, err := fs.ReadFile(fsys, name); err == nil {
+			fp.files[name] = newRendition(body, modTime)
+		}
+	}
+	return fp
+}
+
+// hash returns the hash the fingerprinted name of r carries, a prefix of its strong validator
+func (r *rendition) hash() string {
+	return strings.Trim(r.etag, `"`)[:fingerprintLen]
+}
+
+// name returns the fingerprinted name of the asset name, or name itself when it is not fingerprinted
+func (fp *fingerprints) name(name string) string {
+	r, ok := fp.files[name]
+	if !ok {
+		return name
+	}
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + r.hash() + ext
+}
+
+// resolve returns the original name and the rendition of a fingerprinted name, or nil when the name
+// is not fingerprinted or the hash does not match the current content
+func (fp *fingerprints) resolve(name string) (string, *rendition) {
+	m := fingerprintRe.FindStringSubmatch(name)
+	if m == nil {
+		return "", nil
+	}
+	original := m[1] + m[3]
+	r, ok := fp.files[original]
+	if !ok || r.hash() != m[2] {
+		return "", nil
+	}
+	return original, r
+}
+
+// templateAssets returns the names the templates of t pass as literals to the asset function
+func templateAssets(t *template.Template) []string {
+	var names []string
+	seen := make(map[string]bool)
+	var walk func(node parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.IfNode:
+			walk(&n.BranchNode)
+		case *parse.RangeNode:
+			walk(&n.BranchNode)
+		case *parse.WithNode:
+			walk(&n.BranchNode)
+		case *parse.BranchNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.TemplateNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd)
+			}
+		case *parse.CommandNode:
+			if len(n.Args) == 2 {
+				fn, isIdent := n.Args[0].(*parse.IdentifierNode)
+				arg, isString := n.Args[1].(*parse.StringNode)
+				if isIdent && isString && fn.Ident == "asset" && !seen[arg.Text] {
+					seen[arg.Text] = true
+					names = append(names, arg.Text)
+				}
+			}
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		}
+	}
+	for _, sub := range t.Templates() {
+		if sub.Tree != nil {
+			walk(sub.Tree.Root)
+		}
+	}
+	return names
+}
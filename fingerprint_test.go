@@ -0,0 +1,137 @@
+package swagger
+
+import (
+	"io"
+	"io/fs"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+var bundleRe = regexp.MustCompile(`src="\./(swagger-ui-bundle\.([0-9a-f]{12})\.js)"`)
+
+func Test_Fingerprinted_Assets(t *testing.T) {
+	bundle, err := fs.ReadFile(swaggerFiles.FS, "swagger-ui-bundle.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(t *testing.T, app *fiber.App, url string) (*http.Response, string) {
+		t.Helper()
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp, string(body)
+	}
+
+	app := fiber.New()
+	app.Get("/swag/*", New(Config{Spec: BytesSpec([]byte(`{"swagger":"2.0"}`))}))
+
+	_, page := get(t, app, "/swag/index.html")
+	match := bundleRe.FindStringSubmatch(page)
+	if match == nil {
+		t.Fatal("index.html does not reference a fingerprinted bundle")
+	}
+	for _, name := range []string{"swagger-ui.css", "swagger-ui-standalone-preset.js", "favicon-32x32.png"} {
+		if strings.Contains(page, `"./`+name+`"`) {
+			t.Fatalf("index.html references %s without a fingerprint", name)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		url          string
+		statusCode   int
+		cacheControl string
+	}{
+		{name: "Should serve the fingerprinted name", url: "/swag/" + match[1], statusCode: 200, cacheControl: "public, max-age=31536000, immutable"},
+		{name: "Should keep serving the original name", url: "/swag/swagger-ui-bundle.js", statusCode: 200},
+		{name: "Should return status 404 for a stale fingerprint", url: "/swag/swagger-ui-bundle.000000000000.js", statusCode: 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := get(t, app, tt.url)
+			if resp.StatusCode != tt.statusCode {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, tt.statusCode)
+			}
+			if cc := resp.Header.Get("Cache-Control"); cc != tt.cacheControl {
+				t.Fatalf(`Cache-Control: got %s - expected %s`, cc, tt.cacheControl)
+			}
+			if tt.statusCode != 200 {
+				return
+			}
+			if ct := resp.Header.Get("Content-Type"); !strings.Contains(ct, "javascript") {
+				t.Fatalf(`Content-Type: got %s`, ct)
+			}
+			if body != string(bundle) {
+				t.Fatal("Body differs from the bundle")
+			}
+		})
+	}
+
+	t.Run("Should keep shared caches away from protected assets", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/swag/*", New(Config{
+			Spec:       BytesSpec([]byte(`{"swagger":"2.0"}`)),
+			Authorizer: func(*fiber.Ctx) (bool, error) { return true, nil },
+		}))
+
+		resp, _ := get(t, app, "/swag/"+match[1])
+		if cc := resp.Header.Get("Cache-Control"); cc != "private, max-age=31536000, immutable" {
+			t.Fatalf(`Cache-Control: got %s - expected %s`, cc, "private, max-age=31536000, immutable")
+		}
+	})
+
+	t.Run("Should not fingerprint assets loaded from a CDN", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/swag/*", New(Config{Spec: BytesSpec([]byte(`{"swagger":"2.0"}`)), AssetsURL: "https://cdn.example.com"}))
+
+		_, page := get(t, app, "/swag/index.html")
+		if !strings.Contains(page, `src="https://cdn.example.com/swagger-ui-bundle.js"`) {
+			t.Fatal("index.html does not reference the CDN bundle")
+		}
+	})
+
+	t.Run("Should not remember unknown fingerprinted names", func(t *testing.T) {
+		prints := newFingerprints(swaggerFiles.FS, []string{"swagger-ui-bundle.js", "missing.js"})
+		if len(prints.files) != 1 {
+			t.Fatalf(`Fingerprints: got %v - expected %v`, len(prints.files), 1)
+		}
+		for _, name := range []string{"x1.0123456789ab.js", "x2.0123456789ab.js", "favicon-16x16.0123456789ab.png"} {
+			if _, r := prints.resolve(name); r != nil {
+				t.Fatalf("%s resolved", name)
+			}
+		}
+		if len(prints.files) != 1 {
+			t.Fatalf(`Fingerprints: got %v - expected %v`, len(prints.files), 1)
+		}
+	})
+
+	t.Run("Should fingerprint the assets of custom templates", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/swag/*", New(Config{
+			Spec:          BytesSpec([]byte(`{"swagger":"2.0"}`)),
+			IndexTemplate: `{{define "head"}}<link rel="icon" href="{{asset "favicon-16x16.png"}}">{{end}}`,
+		}))
+
+		_, page := get(t, app, "/swag/index.html")
+		if !regexp.MustCompile(`href="\./favicon-16x16\.[0-9a-f]{12}\.png"`).MatchString(page) {
+			t.Fatal("index.html does not reference a fingerprinted favicon")
+		}
+	})
+}
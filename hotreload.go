@@ -0,0 +1,223 @@
+package swagger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// Endpoint of the Server-Sent Events telling the open pages to reload
+const defaultReloadURL = "_reload"
+
+const (
+	defaultReloadInterval = time.Second
+	// Comment sent to idle event streams, so that closed connections are noticed
+	reloadHeartbeat = 15 * time.Second
+)
+
+// HotReloadConfig configures the development mode serving the document from a file on disk, e.g. the
+// output of "swag init". The file is polled for changes, and the open pages reload when it changes.
+// Polling starts with the first request and stops when the application serving it shuts down.
+type HotReloadConfig struct {
+	// Path of the document, JSON or YAML depending on the extension.
+	// default: "" -> required
+	File string
+
+	// Interval between two checks of the file.
+	// default: 1s
+	Interval time.Duration
+}
+
+// watcher polls a document on disk and notifies its subscribers when the content changes
+type watcher struct {
+	path     string
+	mime     string
+	interval time.Duration
+	// Called after every change, e.g. to validate the new document
+	onChange func()
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	// Closed to end the polling
+	stopped chan struct{}
+
+	mu          sync.RWMutex
+	doc         []byte
+	modTime     time.Time
+	size        int64
+	version     uint64
+	subscribers map[chan uint64]struct{}
+}
+
+func newWatcher(hc *HotReloadConfig) (*watcher, error) {
+	if hc.File == "" {
+		return nil, errors.New("fiber: swagger middleware error -> HotReload requires a File")
+	}
+	w := &watcher{
+		path:        hc.File,
+		mime:        mimeByExtension(hc.File),
+		interval:    hc.Interval,
+		stopped:     make(chan struct{}),
+		subscribers: make(map[chan uint64]struct{}),
+	}
+	if w.interval <= 0 {
+		w.interval = defaultReloadInterval
+	}
+	if _, err := w.poll(); err != nil {
+		return nil, fmt.Errorf("fiber: swagger middleware error -> %w", err)
+	}
+	return w, nil
+}
+
+// Read returns the last content of the file, it implements Spec
+func (w *watcher) Read(_ context.Context) ([]byte, string, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.doc, w.mime, nil
+}
+
+// start polls the file until app shuts down, only the first call has an effect
+func (w *watcher) start(app *fiber.App) {
+	w.startOnce.Do(func() {
+		app.Hooks().OnShutdown(func() error {
+			w.stop()
+			return nil
+		})
+		// The file may have changed since the handler was created
+		w.check()
+		go w.run()
+	})
+}
+
+// stop ends the polling
+func (w *watcher) stop() {
+	w.stopOnce.Do(func() { close(w.stopped) })
+}
+
+// run polls the file until the watcher is stopped
+func (w *watcher) run() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-w.stopped:
+			return
+		}
+		w.check()
+	}
+}
+
+// check polls the file once and calls onChange when the content changed
+func (w *watcher) check() {
+	changed, err := w.poll()
+	if err != nil {
+		// The last good document keeps being served, the file may be rewritten at the moment
+		log.Warnw("swagger: hot reload failed", "file", w.path, "error", err)
+		return
+	}
+	if changed && w.onChange != nil {
+		w.onChange()
+	}
+}
+
+// poll reads the file when its size or modification time changed and reports whether the content changed
+func (w *watcher) poll() (bool, error) {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return false, err
+	}
+
+	w.mu.RLock()
+	same := w.doc != nil && info.ModTime().Equal(w.modTime) && info.Size() == w.size
+	w.mu.RUnlock()
+	if same {
+		return false, nil
+	}
+
+	doc, err := os.ReadFile(w.path)
+	if err != nil {
+		return false, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.modTime, w.size = info.ModTime(), info.Size()
+	if w.doc != nil && bytes.Equal(w.doc, doc) {
+		return false, nil
+	}
+	w.doc = doc
+	w.version++
+	for ch := range w.subscribers {
+		// Subscribers only need the latest version, a pending one is replaced
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- w.version
+	}
+	return true, nil
+}
+
+func (w *watcher) subscribe() chan uint64 {
+	ch := make(chan uint64, 1)
+	w.mu.Lock()
+	w.subscribers[ch] = struct{}{}
+	w.mu.Unlock()
+	return ch
+}
+
+func (w *watcher) unsubscribe(ch chan uint64) {
+	w.mu.Lock()
+	delete(w.subscribers, ch)
+	w.mu.Unlock()
+}
+
+// serveEvents streams a "reload" event to the page every time the document changes
+func (w *watcher) serveEvents(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	// Keeps reverse proxies such as nginx from buffering the stream
+	c.Set("X-Accel-Buffering", "no")
+
+	// Closed when the server shuts down, which otherwise waits for the stream to end
+	done := c.Context().Done()
+	ch := w.subscribe()
+	c.Context().SetBodyStreamWriter(func(bw *bufio.Writer) {
+		defer w.unsubscribe(ch)
+
+		heartbeat := time.NewTicker(reloadHeartbeat)
+		defer heartbeat.Stop()
+
+		if _, err := bw.WriteString("retry: 1000\n\n"); err != nil || bw.Flush() != nil {
+			return
+		}
+		for {
+			var msg string
+			select {
+			case version := <-ch:
+				msg = "event: reload\ndata: " + strconv.FormatUint(version, 10) + "\n\n"
+			case <-heartbeat.C:
+				msg = ": ping\n\n"
+			case <-done:
+				return
+			}
+			if _, err := bw.WriteString(msg); err != nil {
+				return
+			}
+			if err := bw.Flush(); err != nil {
+				return
+			}
+		}
+	})
+	return nil
+}
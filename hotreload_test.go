@@ -0,0 +1,163 @@
+package swagger
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_Hot_Reload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "swagger.json")
+	if err := os.WriteFile(file, []byte(`{"swagger":"2.0","info":{"title":"v1"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/swag/*", New(Config{HotReload: &HotReloadConfig{File: file, Interval: 10 * time.Millisecond}}))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = app.Listener(ln) }()
+	defer func() { _ = app.Shutdown() }()
+	base := "http://" + ln.Addr().String() + "/swag/"
+
+	get := func(t *testing.T, url string) string {
+		t.Helper()
+		resp, err := http.Get(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(body)
+	}
+
+	if body := get(t, base+"index.html"); !strings.Contains(body, "new EventSource(new URL('./_reload', window.location.href))") {
+		t.Fatal("index.html does not subscribe to the reload events")
+	}
+	if body := get(t, base+"doc.json"); body != `{"swagger":"2.0","info":{"title":"v1"}}` {
+		t.Fatalf("Body: got %s", body)
+	}
+
+	resp, err := http.Get(base + "_reload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf(`Content-Type: got %s - expected %s`, ct, "text/event-stream")
+	}
+
+	events := make(chan string, 8)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			events <- scanner.Text()
+		}
+		close(events)
+	}()
+	next := func(t *testing.T) string {
+		t.Helper()
+		select {
+		case line := <-events:
+			return line
+		case <-time.After(2 * time.Second):
+			t.Fatal("no event received")
+		}
+		return ""
+	}
+
+	if line := next(t); line != "retry: 1000" {
+		t.Fatalf("first line: got %s", line)
+	}
+	next(t)
+
+	if err := os.WriteFile(file, []byte(`{"swagger":"2.0","info":{"title":"version 2"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if line := next(t); line != "event: reload" {
+		t.Fatalf("event: got %s - expected %s", line, "event: reload")
+	}
+	if line := next(t); line != "data: 2" {
+		t.Fatalf("data: got %s - expected %s", line, "data: 2")
+	}
+
+	if body := get(t, base+"doc.json"); body != `{"swagger":"2.0","info":{"title":"version 2"}}` {
+		t.Fatalf("Body: got %s", body)
+	}
+}
+
+func Test_Hot_Reload_Stop(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "swagger.json")
+	if err := os.WriteFile(file, []byte(`{"swagger":"2.0"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Watchers of the previous tests stop with their app
+	waitWatchers(t, 0)
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/swag/*", New(Config{HotReload: &HotReloadConfig{File: file, Interval: time.Millisecond}}))
+	if n := runningWatchers(); n != 0 {
+		t.Fatalf(`Watchers before the first request: got %v - expected %v`, n, 0)
+	}
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/swag/doc.json", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 200)
+	}
+	if n := runningWatchers(); n != 1 {
+		t.Fatalf(`Watchers after the first request: got %v - expected %v`, n, 1)
+	}
+
+	if err := app.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+	waitWatchers(t, 0)
+}
+
+func Test_Hot_Reload_Missing_File(t *testing.T) {
+	if _, err := newWatcher(&HotReloadConfig{File: filepath.Join(t.TempDir(), "missing.json")}); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+	if _, err := newWatcher(&HotReloadConfig{}); err == nil {
+		t.Fatal("expected an error without File")
+	}
+}
+
+// runningWatchers counts the goroutines polling a hot reload file, including those not scheduled yet
+func runningWatchers() int {
+	buf := make([]byte, 1<<20)
+	buf = buf[:runtime.Stack(buf, true)]
+	return bytes.Count(buf, []byte("created by github.com/gofiber/swagger.(*watcher).start"))
+}
+
+// waitWatchers waits for n watchers to be running
+func waitWatchers(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runningWatchers() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf(`Watchers: got %v - expected %v`, runningWatchers(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
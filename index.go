@@ -7,24 +7,32 @@ const indexTmpl string = `
   <head>
     <meta charset="UTF-8">
     <title>{{.Title}}</title>
+    {{- if .Offline}}
+    <link rel="stylesheet" type="text/css" href="./fonts/fonts.css">
+    {{- else}}
     <link href="https://fonts.googleapis.com/css?family=Open+Sans:400,700|Source+Code+Pro:300,600|Titillium+Web:400,600,700" rel="stylesheet">
-    <link rel="stylesheet" type="text/css" href="./swagger-ui.css" >
-    <link rel="icon" type="image/png" href="./favicon-32x32.png" sizes="32x32" />
-    <link rel="icon" type="image/png" href="./favicon-16x16.png" sizes="16x16" />
+    {{- end}}
+    <link rel="stylesheet" type="text/css" href="{{asset "swagger-ui.css"}}" >
+    <link rel="icon" type="image/png" href="{{asset "favicon-32x32.png"}}" sizes="32x32" />
+    <link rel="icon" type="image/png" href="{{asset "favicon-16x16.png"}}" sizes="16x16" />
+    <style{{with nonce}} nonce="{{.}}"{{end}}>
+      .swagger-ui-sprites { position: absolute; width: 0; height: 0; }
+    </style>
     {{- if .CustomStyle}}
-      <style>
+      <style{{with nonce}} nonce="{{.}}"{{end}}>
         body { margin: 0; }
         {{.CustomStyle}}
       </style>
     {{- end}}
     {{- if .CustomScript}}
-      <script>
+      <script{{with nonce}} nonce="{{.}}"{{end}}>
         {{.CustomScript}}
       </script>
     {{- end}}
+    {{- block "head" .}}{{end}}
   </head>
   <body>
-    <svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" style="position:absolute;width:0;height:0">
+    <svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" class="swagger-ui-sprites">
       <defs>
         <symbol viewBox="0 0 20 20" id="unlocked">
               <path d="M15.8 8H14V5.6C14 2.703 12.665 1 10 1 7.334 1 6 2.703 6 5.6V6h2v-.801C8 3.754 8.797 3 10 3c1.203 0 2 .754 2 2.199V8H4c-.553 0-1 .646-1 1.199V17c0 .549.428 1.139.951 1.307l1.197.387C5.672 18.861 6.55 19 7.1 19h5.8c.549 0 1.428-.139 1.951-.307l1.196-.387c.524-.167.953-.757.953-1.306V9.199C17 8.646 16.352 8 15.8 8z"></path>
@@ -49,12 +57,27 @@ const indexTmpl string = `
         </symbol>
       </defs>
     </svg>
+    {{- block "before_ui" .}}{{end}}
     <div id="swagger-ui"></div>
-    <script src="./swagger-ui-bundle.js"> </script>
-    <script src="./swagger-ui-standalone-preset.js"> </script>
-    <script>
+    <script src="{{asset "swagger-ui-bundle.js"}}"> </script>
+    <script src="{{asset "swagger-ui-standalone-preset.js"}}"> </script>
+    {{- if .HotReload}}
+    <script{{with nonce}} nonce="{{.}}"{{end}}>
+      // Reloads the page when the document changes on disk
+      new EventSource(new URL('./_reload', window.location.href)).addEventListener('reload', function() {
+        window.location.reload();
+      });
+    </script>
+    {{- end}}
+    <script{{with nonce}} nonce="{{.}}"{{end}}>
     window.onload = function() {
+      {{- if .ExternalConfig}}
+      config = {configUrl: {{.ConfigURL}}, queryConfigEnabled: {{.QueryConfigEnabled}}};
+      {{- else}}
       config = {{.}};
+      config.filter = {{.Filter.Value}}
+      config.syntaxHighlight = {{.SyntaxHighlight.Value}}
+      {{- end}}
       config.dom_id = '#swagger-ui';
       config.plugins = [
         {{- range $plugin := .Plugins }}
@@ -66,15 +89,31 @@ const indexTmpl string = `
           {{$preset}},
         {{- end}}
       ];
-      config.filter = {{.Filter.Value}}
-      config.syntaxHighlight = {{.SyntaxHighlight.Value}}
       {{if .TagsSorter}}
         config.tagsSorter = {{.TagsSorter}}
       {{end}}
       {{if .OnComplete}}
         config.onComplete = {{.OnComplete}}
       {{end}}
-      {{if .RequestInterceptor}}
+      {{if .Proxy}}
+        // "Try it out" requests to other origins go through the proxy of the middleware
+        const proxyURL = new URL('./_proxy/', window.location.href).href;
+        const proxyRequest = function(request) {
+          const target = new URL(request.url, window.location.href);
+          if (!request.loadSpec && target.origin !== window.location.origin) {
+            request.url = proxyURL + target.protocol.slice(0, -1) + '/' + target.host + target.pathname + target.search;
+          }
+          return request;
+        };
+        {{if .RequestInterceptor}}
+          const requestInterceptor = {{.RequestInterceptor}};
+          config.requestInterceptor = function(request) {
+            return Promise.resolve(requestInterceptor(request)).then(proxyRequest);
+          };
+        {{else}}
+          config.requestInterceptor = proxyRequest;
+        {{end}}
+      {{else if .RequestInterceptor}}
         config.requestInterceptor = {{.RequestInterceptor}}
       {{end}}
       {{if .ResponseInterceptor}}
@@ -102,6 +141,7 @@ const indexTmpl string = `
       window.ui = ui
     }
     </script>
+    {{- block "after_ui" .}}{{end}}
   </body>
 </html>
 `
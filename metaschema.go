@@ -0,0 +1,105 @@
+package swagger
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// JSON Schemas of Swagger 2.0 and OpenAPI 3.0, along with the draft-04 meta-schema the former refers to
+//
+//go:embed schemas/*.json
+var metaSchemaFS embed.FS
+
+// Base of the references to the draft-04 meta-schema
+const draft04URL = "http://json-schema.org/draft-04/schema#"
+
+var (
+	metaSchemasOnce sync.Once
+	metaSchemas     map[string]map[string]interface{}
+	metaSchemasErr  error
+)
+
+// metaSchema returns the JSON Schema of the version of the document root, or nil if there is none.
+// OpenAPI 3.1 is described with $dynamicRef, such documents only get the structural checks.
+func metaSchema(root map[string]interface{}) (map[string]interface{}, error) {
+	var name string
+	version, _ := root["openapi"].(string)
+	switch {
+	case root["swagger"] == "2.0":
+		name = "swagger-2.0.json"
+	case strings.HasPrefix(version, "3.0."):
+		name = "openapi-3.0.json"
+	default:
+		return nil, nil
+	}
+
+	metaSchemasOnce.Do(loadMetaSchemas)
+	if metaSchemasErr != nil {
+		return nil, metaSchemasErr
+	}
+	return metaSchemas[name], nil
+}
+
+func loadMetaSchemas() {
+	draft04, err := readMetaSchema("json-schema-draft-04.json")
+	if err != nil {
+		metaSchemasErr = err
+		return
+	}
+	schemas := make(map[string]map[string]interface{}, 2)
+	for _, name := range []string{"swagger-2.0.json", "openapi-3.0.json"} {
+		schema, err := readMetaSchema(name)
+		if err != nil {
+			metaSchemasErr = err
+			return
+		}
+		schemas[name], _ = inlineDraft04(schema, draft04, false, 0).(map[string]interface{})
+	}
+	metaSchemas = schemas
+}
+
+func readMetaSchema(name string) (map[string]interface{}, error) {
+	b, err := metaSchemaFS.ReadFile("schemas/" + name)
+	if err != nil {
+		return nil, err
+	}
+	var schema map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&schema); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return schema, nil
+}
+
+// inlineDraft04 copies node, replacing its references to the draft-04 meta-schema with their targets.
+// Within those targets, local references point to the meta-schema as well.
+func inlineDraft04(node interface{}, draft04 map[string]interface{}, inDraft04 bool, depth int) interface{} {
+	switch t := node.(type) {
+	case map[string]interface{}:
+		ref, _ := t["$ref"].(string)
+		switch {
+		case depth > maxSchemaDepth:
+		case strings.HasPrefix(ref, draft04URL):
+			return inlineDraft04(resolvePointer(draft04, "#"+strings.TrimPrefix(ref, draft04URL)), draft04, true, depth+1)
+		case inDraft04 && strings.HasPrefix(ref, "#"):
+			return inlineDraft04(resolvePointer(draft04, ref), draft04, true, depth+1)
+		}
+		out := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			out[k] = inlineDraft04(v, draft04, inDraft04, depth)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, v := range t {
+			out[i] = inlineDraft04(v, draft04, inDraft04, depth)
+		}
+		return out
+	}
+	return node
+}
@@ -0,0 +1,297 @@
+package swagger
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Upper bound of nested objects in synthesized responses, guards against recursive schemas
+const maxMockDepth = 8
+
+// Mock returns an app answering every operation of the swag instance named instanceName with a documented
+// response: its example, or a value synthesized from its schema. Clients pick the response with a
+// "Prefer: code=404, example=notFound" header, otherwise the first documented success is sent.
+// Mount the app in another one to add middlewares such as CORS.
+func Mock(instanceName string) (*fiber.App, error) {
+	return MockSpec(SwagSpec(instanceName))
+}
+
+// MockSpec returns an app like Mock, for the document read from spec.
+func MockSpec(spec Spec) (*fiber.App, error) {
+	doc, isYAML, err := readSpec(context.Background(), spec)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newAPIModel(doc, isYAML)
+	if err != nil {
+		return nil, err
+	}
+
+	app := fiber.New()
+	// Operations are sorted so that literal segments are registered, and matched, first
+	for _, op := range m.operations {
+		op := op
+		app.Add(op.method, fiberPath(op.path), func(c *fiber.Ctx) error {
+			return mockResponse(c, m, op)
+		})
+	}
+	return app, nil
+}
+
+// fiberPath converts a path template to a Fiber route
+func fiberPath(template string) string {
+	i := 0
+	return pathParamRe.ReplaceAllStringFunc(template, func(string) string {
+		i++
+		return ":p" + strconv.Itoa(i)
+	})
+}
+
+// parsePrefer returns the preferences of a Prefer header
+func parsePrefer(header string) map[string]string {
+	prefs := make(map[string]string)
+	for _, part := range strings.FieldsFunc(header, func(r rune) bool { return r == ',' || r == ';' }) {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		prefs[strings.ToLower(strings.TrimSpace(key))] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	return prefs
+}
+
+func mockResponse(c *fiber.Ctx, m *apiModel, op *apiOperation) error {
+	prefs := parsePrefer(c.Get("Prefer"))
+
+	code, raw := selectResponse(op.responses, prefs["code"])
+	if raw == nil {
+		return fiber.ErrNotImplemented
+	}
+	status := fiber.StatusOK
+	if n, err := strconv.Atoi(strings.ReplaceAll(code, "X", "0")); err == nil {
+		status = n
+	}
+	c.Status(status)
+
+	response := m.deref(raw)
+	for name, raw := range mapOf(response["headers"]) {
+		header := m.deref(raw)
+		if header == nil || strings.EqualFold(name, fiber.HeaderContentType) {
+			continue
+		}
+		if v := mockValue(m, header, "", 0); v != nil {
+			c.Set(name, scalarString(v))
+		}
+	}
+
+	content := mapOf(response["content"])
+	if len(content) == 0 {
+		return c.Send(nil)
+	}
+
+	// JSON first, then as negotiated with the Accept header
+	types := sortedKeys(content)
+	sort.SliceStable(types, func(i, j int) bool {
+		return isJSONMediaType(types[i]) && !isJSONMediaType(types[j])
+	})
+	mime := c.Accepts(types...)
+	if mime == "" {
+		mime = types[0]
+	}
+	media := mapOf(content[mime])
+
+	value := mockValue(m, media, prefs["example"], 0)
+	c.Set(fiber.HeaderContentType, mime)
+	if s, ok := value.(string); ok && !isJSONMediaType(mime) {
+		return c.SendString(s)
+	}
+	body, err := marshalJSON(value)
+	if err != nil {
+		return err
+	}
+	return c.Send(body)
+}
+
+// selectResponse returns the response of the preferred status code, or the first documented success
+// when no valid status code is preferred
+func selectResponse(responses map[string]interface{}, preferred string) (string, interface{}) {
+	if isStatusCode(preferred) {
+		for _, code := range []string{preferred, preferred[:1] + "XX"} {
+			if r, ok := responses[code]; ok {
+				return preferred, r
+			}
+		}
+		if r, ok := responses["default"]; ok {
+			return preferred, r
+		}
+	}
+
+	codes := sortedKeys(responses)
+	for _, code := range codes {
+		if strings.HasPrefix(code, "2") {
+			return code, responses[code]
+		}
+	}
+	if r, ok := responses["default"]; ok {
+		return "200", r
+	}
+	if len(codes) > 0 {
+		return codes[0], responses[codes[0]]
+	}
+	return "", nil
+}
+
+// isStatusCode reports whether code is a three-digit HTTP status code, from 100 to 599
+func isStatusCode(code string) bool {
+	n, err := strconv.Atoi(code)
+	return err == nil && len(code) == 3 && n >= 100 && n <= 599
+}
+
+// mockValue returns the example of a media type or parameter object, named name if possible,
+// or a value synthesized from its schema
+func mockValue(m *apiModel, obj map[string]interface{}, name string, depth int) interface{} {
+	examples := mapOf(obj["examples"])
+	if example := m.deref(examples[name]); name != "" && example != nil {
+		return example["value"]
+	}
+	if example, ok := obj["example"]; ok {
+		return example
+	}
+	if keys := sortedKeys(examples); len(keys) > 0 {
+		if example := m.deref(examples[keys[0]]); example != nil {
+			return example["value"]
+		}
+	}
+	return synthesize(m, obj["schema"], depth)
+}
+
+// synthesize builds a value matching schema
+func synthesize(m *apiModel, raw interface{}, depth int) interface{} {
+	s := m.deref(raw)
+	if s == nil || depth > maxMockDepth {
+		return nil
+	}
+
+	for _, key := range []string{"example", "default", "const"} {
+		if v, ok := s[key]; ok {
+			return v
+		}
+	}
+	if enum, ok := s["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	if examples, ok := s["examples"].([]interface{}); ok && len(examples) > 0 {
+		return examples[0]
+	}
+
+	if allOf, ok := s["allOf"].([]interface{}); ok {
+		merged := make(map[string]interface{})
+		for _, sub := range allOf {
+			for k, v := range mapOf(synthesize(m, sub, depth+1)) {
+				merged[k] = v
+			}
+		}
+		for k, v := range mapOf(synthesizeObject(m, s, depth)) {
+			merged[k] = v
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if list, ok := s[key].([]interface{}); ok && len(list) > 0 {
+			return synthesize(m, list[0], depth+1)
+		}
+	}
+
+	t := schemaType(s)
+	if t == "" && s["properties"] != nil {
+		t = "object"
+	}
+	switch t {
+	case "object":
+		return synthesizeObject(m, s, depth)
+	case "array":
+		n := 1
+		if minItems, ok := schemaInt(s, "minItems"); ok && minItems > n {
+			n = minItems
+		}
+		items := make([]interface{}, 0, n)
+		for i := 0; i < n; i++ {
+			item := synthesize(m, s["items"], depth+1)
+			if item == nil {
+				break
+			}
+			items = append(items, item)
+		}
+		return items
+	case "integer", "number":
+		if minimum, ok := s["minimum"].(json.Number); ok {
+			return minimum
+		}
+		return json.Number("0")
+	case "boolean":
+		return true
+	case "string":
+		return synthesizeString(s)
+	}
+	return nil
+}
+
+func synthesizeObject(m *apiModel, s map[string]interface{}, depth int) map[string]interface{} {
+	obj := make(map[string]interface{})
+	for name, raw := range mapOf(s["properties"]) {
+		prop := m.deref(raw)
+		if writeOnly, _ := prop["writeOnly"].(bool); writeOnly {
+			continue
+		}
+		// Properties cut by the depth limit are left out rather than set to null
+		if v := synthesize(m, raw, depth+1); v != nil {
+			obj[name] = v
+		}
+	}
+	return obj
+}
+
+func synthesizeString(s map[string]interface{}) string {
+	switch s["format"] {
+	case "date-time":
+		return "1970-01-01T00:00:00Z"
+	case "date":
+		return "1970-01-01"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	case "ipv4":
+		return "192.0.2.1"
+	case "ipv6":
+		return "2001:db8::1"
+	case "byte", "binary":
+		return ""
+	}
+	v := "string"
+	if minLength, ok := schemaInt(s, "minLength"); ok && minLength > len(v) {
+		v += strings.Repeat("x", minLength-len(v))
+	}
+	return v
+}
+
+func mapOf(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+// scalarString formats a synthesized value for a header
+func scalarString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
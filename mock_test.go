@@ -0,0 +1,222 @@
+package swagger
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+const mockDoc = `{
+	"openapi": "3.0.3",
+	"servers": [{"url": "https://api.example.com/v1"}],
+	"paths": {
+		"/pets": {
+			"get": {
+				"responses": {
+					"200": {
+						"description": "ok",
+						"headers": {"X-Total": {"schema": {"type": "integer", "minimum": 1}}},
+						"content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}}}
+					}
+				}
+			},
+			"post": {
+				"responses": {
+					"201": {
+						"description": "created",
+						"content": {"application/json": {
+							"examples": {
+								"cat": {"value": {"id": 1, "name": "Tom"}},
+								"dog": {"$ref": "#/components/examples/Dog"}
+							}
+						}}
+					},
+					"4XX": {
+						"description": "error",
+						"content": {"application/problem+json": {"example": {"title": "Bad Request"}}}
+					}
+				}
+			}
+		},
+		"/pets/mine": {
+			"get": {"responses": {"204": {"description": "nothing"}}}
+		},
+		"/pets/{id}": {
+			"get": {
+				"responses": {
+					"200": {"description": "ok", "content": {"text/plain": {"example": "Tom"}, "application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}},
+					"default": {"description": "error"}
+				}
+			}
+		}
+	},
+	"components": {
+		"examples": {"Dog": {"value": {"id": 2, "name": "Rex"}}},
+		"schemas": {
+			"Pet": {
+				"allOf": [{"$ref": "#/components/schemas/Entity"}],
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string"},
+					"born": {"type": "string", "format": "date"},
+					"kind": {"type": "string", "enum": ["cat", "dog"]},
+					"secret": {"type": "string", "writeOnly": true},
+					"friends": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}
+				}
+			},
+			"Entity": {"type": "object", "properties": {"id": {"type": "integer", "format": "int64"}}}
+		}
+	}
+}`
+
+func Test_Mock(t *testing.T) {
+	app, err := MockSpec(BytesSpec([]byte(mockDoc)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		url         string
+		headers     map[string]string
+		statusCode  int
+		contentType string
+		body        string
+		header      [2]string
+	}{
+		{
+			name:        "Synthesized from the schema",
+			method:      http.MethodGet,
+			url:         "/v1/pets/7",
+			statusCode:  200,
+			contentType: "application/json",
+			body:        `{"born":"1970-01-01","friends":[{"born":"1970-01-01","friends":[{"born":"1970-01-01","friends":[{"born":"1970-01-01","friends":[{}],"id":0,"kind":"cat","name":"string"}],"id":0,"kind":"cat","name":"string"}],"id":0,"kind":"cat","name":"string"}],"id":0,"kind":"cat","name":"string"}`,
+		},
+		{
+			name:        "Negotiated media type",
+			method:      http.MethodGet,
+			url:         "/v1/pets/7",
+			headers:     map[string]string{"Accept": "text/plain"},
+			statusCode:  200,
+			contentType: "text/plain",
+			body:        "Tom",
+		},
+		{
+			name:       "Literal path and headers",
+			method:     http.MethodGet,
+			url:        "/v1/pets/mine",
+			statusCode: 204,
+		},
+		{
+			name:        "Synthesized headers",
+			method:      http.MethodGet,
+			url:         "/v1/pets",
+			statusCode:  200,
+			contentType: "application/json",
+			header:      [2]string{"X-Total", "1"},
+		},
+		{
+			name:        "First example",
+			method:      http.MethodPost,
+			url:         "/v1/pets",
+			statusCode:  201,
+			contentType: "application/json",
+			body:        `{"id":1,"name":"Tom"}`,
+		},
+		{
+			name:        "Preferred example",
+			method:      http.MethodPost,
+			url:         "/v1/pets",
+			headers:     map[string]string{"Prefer": "example=dog"},
+			statusCode:  201,
+			contentType: "application/json",
+			body:        `{"id":2,"name":"Rex"}`,
+		},
+		{
+			name:        "Preferred status code within a range",
+			method:      http.MethodPost,
+			url:         "/v1/pets",
+			headers:     map[string]string{"Prefer": `code=409, example="cat"`},
+			statusCode:  409,
+			contentType: "application/problem+json",
+			body:        `{"title":"Bad Request"}`,
+		},
+		{
+			name:       "Preferred default response",
+			method:     http.MethodGet,
+			url:        "/v1/pets/7",
+			headers:    map[string]string{"Prefer": "code=500"},
+			statusCode: 500,
+		},
+		{
+			name:        "Invalid preferred status code 1",
+			method:      http.MethodGet,
+			url:         "/v1/pets/7",
+			headers:     map[string]string{"Prefer": "code=1"},
+			statusCode:  200,
+			contentType: "application/json",
+		},
+		{
+			name:        "Invalid preferred status code 999",
+			method:      http.MethodGet,
+			url:         "/v1/pets/7",
+			headers:     map[string]string{"Prefer": "code=999"},
+			statusCode:  200,
+			contentType: "application/json",
+		},
+		{
+			name:        "Invalid preferred status code abc",
+			method:      http.MethodGet,
+			url:         "/v1/pets/7",
+			headers:     map[string]string{"Prefer": "code=abc"},
+			statusCode:  200,
+			contentType: "application/json",
+		},
+		{
+			name:        "Invalid preferred status code +20",
+			method:      http.MethodGet,
+			url:         "/v1/pets/7",
+			headers:     map[string]string{"Prefer": "code=+20"},
+			statusCode:  200,
+			contentType: "application/json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tt.statusCode {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, tt.statusCode)
+			}
+			if ct := resp.Header.Get("Content-Type"); tt.contentType != "" && ct != tt.contentType {
+				t.Fatalf(`Content-Type: got %s - expected %s`, ct, tt.contentType)
+			}
+			if tt.header[0] != "" && resp.Header.Get(tt.header[0]) != tt.header[1] {
+				t.Fatalf(`%s: got %s - expected %s`, tt.header[0], resp.Header.Get(tt.header[0]), tt.header[1])
+			}
+			if tt.body == "" {
+				return
+			}
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(body) != tt.body {
+				t.Fatalf("Body: got %s - expected %s", body, tt.body)
+			}
+		})
+	}
+}
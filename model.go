@@ -0,0 +1,253 @@
+package swagger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// apiModel is the OpenAPI 3 form of a document, indexed for the request and response validators and the mock server
+type apiModel struct {
+	root       map[string]interface{}
+	operations []*apiOperation
+}
+
+// apiOperation is an operation of the document along with its path-level parameters
+type apiOperation struct {
+	method string
+	// Path template prefixed with the base path of the document
+	path        string
+	re          *regexp.Regexp
+	names       []string
+	op          map[string]interface{}
+	params      []map[string]interface{}
+	requestBody map[string]interface{}
+	responses   map[string]interface{}
+	literal     int
+}
+
+// newAPIModel builds the model of a document, Swagger 2.0 documents are converted to OpenAPI 3
+func newAPIModel(doc []byte, isYAML bool) (*apiModel, error) {
+	var err error
+	if isYAML {
+		if doc, err = yamlToJSON(doc); err != nil {
+			return nil, err
+		}
+	}
+	if doc, err = convertToOpenAPI3(doc, "3.0"); err != nil {
+		return nil, err
+	}
+
+	var root map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	if err := dec.Decode(&root); err != nil {
+		return nil, fmt.Errorf("invalid json document: %w", err)
+	}
+
+	m := &apiModel{root: root}
+	base := basePath(root)
+	paths, _ := root["paths"].(map[string]interface{})
+	for p, raw := range paths {
+		item := m.deref(raw)
+		if item == nil {
+			continue
+		}
+		full := joinPath(base, p)
+		re, names, literal := compilePathTemplate(full)
+		shared := m.parameters(item["parameters"], nil)
+
+		for _, method := range operationMethods {
+			op, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			responses, _ := op["responses"].(map[string]interface{})
+			m.operations = append(m.operations, &apiOperation{
+				method:      strings.ToUpper(method),
+				path:        full,
+				re:          re,
+				names:       names,
+				op:          op,
+				params:      m.parameters(op["parameters"], shared),
+				requestBody: m.deref(op["requestBody"]),
+				responses:   responses,
+				literal:     literal,
+			})
+		}
+	}
+
+	// Literal segments win over parameters, e.g. /users/me over /users/{id}
+	sort.SliceStable(m.operations, func(i, j int) bool {
+		a, b := m.operations[i], m.operations[j]
+		if len(a.names) != len(b.names) {
+			return len(a.names) < len(b.names)
+		}
+		if a.literal != b.literal {
+			return a.literal > b.literal
+		}
+		return a.path < b.path
+	})
+	return m, nil
+}
+
+// compilePathTemplate returns the expression matching the paths of a template, the names of its parameters
+// and the length of its literal parts
+func compilePathTemplate(template string) (*regexp.Regexp, []string, int) {
+	var (
+		sb      strings.Builder
+		names   []string
+		literal int
+		last    int
+	)
+	sb.WriteByte('^')
+	for _, loc := range pathParamRe.FindAllStringSubmatchIndex(template, -1) {
+		sb.WriteString(regexp.QuoteMeta(template[last:loc[0]]))
+		literal += loc[0] - last
+		sb.WriteString("([^/]+)")
+		names = append(names, template[loc[2]:loc[3]])
+		last = loc[1]
+	}
+	sb.WriteString(regexp.QuoteMeta(template[last:]))
+	literal += len(template) - last
+	sb.WriteString("/?$")
+	return regexp.MustCompile(sb.String()), names, literal
+}
+
+// parameters resolves a list of parameters, those of the operation replace the shared ones with the same name and location
+func (m *apiModel) parameters(raw interface{}, shared []map[string]interface{}) []map[string]interface{} {
+	list, _ := raw.([]interface{})
+	params := make([]map[string]interface{}, 0, len(shared)+len(list))
+	key := func(p map[string]interface{}) string {
+		return fmt.Sprint(p["in"], "\x00", p["name"])
+	}
+
+	own := make(map[string]bool, len(list))
+	for _, raw := range list {
+		if p := m.deref(raw); p != nil {
+			params = append(params, p)
+			own[key(p)] = true
+		}
+	}
+	for _, p := range shared {
+		if !own[key(p)] {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// match returns the operation serving a request and the values of its path parameters, or nil.
+// HEAD requests fall back to the GET operation of the path.
+func (m *apiModel) match(method, p string) (*apiOperation, map[string]string) {
+	var fallback *apiOperation
+	var fallbackValues map[string]string
+	for _, op := range m.operations {
+		if op.method != method && !(method == "HEAD" && op.method == "GET") {
+			continue
+		}
+		sub := op.re.FindStringSubmatch(p)
+		if sub == nil {
+			continue
+		}
+		values := make(map[string]string, len(op.names))
+		for i, name := range op.names {
+			values[name] = sub[i+1]
+		}
+		if op.method == method {
+			return op, values
+		}
+		if fallback == nil {
+			fallback, fallbackValues = op, values
+		}
+	}
+	return fallback, fallbackValues
+}
+
+// resolve returns the value a local reference points to, or nil
+func (m *apiModel) resolve(ref string) interface{} {
+	return resolvePointer(m.root, ref)
+}
+
+// deref returns the object v, following its references
+func (m *apiModel) deref(v interface{}) map[string]interface{} {
+	for i := 0; i < maxSchemaDepth; i++ {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		ref, ok := obj["$ref"].(string)
+		if !ok {
+			return obj
+		}
+		v = m.resolve(ref)
+	}
+	return nil
+}
+
+// resolvePointer returns the value of root a local reference such as "#/components/schemas/User" points to, or nil
+func resolvePointer(root interface{}, ref string) interface{} {
+	if !strings.HasPrefix(ref, "#") {
+		return nil
+	}
+	node := root
+	pointer := strings.TrimPrefix(ref, "#")
+	if pointer == "" {
+		return node
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		switch t := node.(type) {
+		case map[string]interface{}:
+			node = t[unescapePointer(token)]
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(t) {
+				return nil
+			}
+			node = t[i]
+		default:
+			return nil
+		}
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// modelCache builds the model of a spec on first use
+type modelCache struct {
+	spec  Spec
+	mu    sync.RWMutex
+	model *apiModel
+}
+
+func (mc *modelCache) get(ctx context.Context) (*apiModel, error) {
+	mc.mu.RLock()
+	m := mc.model
+	mc.mu.RUnlock()
+	if m != nil {
+		return m, nil
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if mc.model != nil {
+		return mc.model, nil
+	}
+
+	doc, isYAML, err := readSpec(ctx, mc.spec)
+	if err != nil {
+		return nil, err
+	}
+	if mc.model, err = newAPIModel(doc, isYAML); err != nil {
+		return nil, err
+	}
+	return mc.model, nil
+}
@@ -0,0 +1,18 @@
+package swagger
+
+import (
+	"embed"
+	"strings"
+)
+
+// Fonts referenced by the index page in offline mode
+//
+//go:embed fonts
+var fontFiles embed.FS
+
+const fontsDir = "fonts/"
+
+// isExternalURL reports whether u is loaded from another origin
+func isExternalURL(u string) bool {
+	return strings.HasPrefix(u, "//") || strings.Contains(u, "://")
+}
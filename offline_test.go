@@ -0,0 +1,113 @@
+package swagger
+
+import (
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SVG namespaces are identifiers and never fetched
+var xmlnsRe = regexp.MustCompile(`xmlns(:\w+)?="[^"]*"`)
+
+func Test_Offline(t *testing.T) {
+	app := fiber.New()
+	app.Get("/swag/*", New(Config{
+		Spec:    BytesSpec([]byte(`{"swagger":"2.0"}`)),
+		Offline: true,
+	}))
+
+	t.Run("Should only reference same-origin assets", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/swag/index.html", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		page := xmlnsRe.ReplaceAllString(string(body), "")
+		if strings.Contains(page, "://") || strings.Contains(page, `"//`) {
+			t.Fatalf("index.html references an absolute url:\n%s", page)
+		}
+		if !strings.Contains(page, `"validatorUrl":"none"`) {
+			t.Fatal("index.html does not disable the validator")
+		}
+		if !strings.Contains(page, `href="./fonts/fonts.css"`) {
+			t.Fatal("index.html does not reference the bundled fonts")
+		}
+	})
+
+	tests := []struct {
+		url         string
+		contentType string
+	}{
+		{url: "/swag/fonts/fonts.css", contentType: "text/css"},
+		{url: "/swag/fonts/open-sans-400.woff2", contentType: "font/woff2"},
+		{url: "/swag/fonts/source-code-pro-500.woff2", contentType: "font/woff2"},
+	}
+
+	for _, tt := range tests {
+		t.Run("Should serve "+tt.url, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != 200 {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 200)
+			}
+			if ct := resp.Header.Get("Content-Type"); ct != tt.contentType {
+				t.Fatalf(`Content-Type: got %s - expected %s`, ct, tt.contentType)
+			}
+		})
+	}
+
+	t.Run("Should not serve fonts when online", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/swag/*", New(Config{Spec: BytesSpec([]byte(`{"swagger":"2.0"}`))}))
+
+		req, err := http.NewRequest(http.MethodGet, "/swag/fonts/fonts.css", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != 404 {
+			t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 404)
+		}
+	})
+
+	t.Run("Should require same-origin renderer assets", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected a panic")
+			}
+		}()
+
+		New(Config{Offline: true, Renderer: ElementsConfig{ScriptURL: "/static/elements.js"}})
+	})
+
+	t.Run("Should accept same-origin renderer assets", func(t *testing.T) {
+		New(Config{Offline: true, Renderer: RedocConfig{ScriptURL: "/static/redoc.standalone.js"}})
+	})
+}
@@ -0,0 +1,604 @@
+package swagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	defaultOpenAPIURL     = "openapi.json"
+	defaultOpenAPIYAMLURL = "openapi.yaml"
+)
+
+// openAPIVersion returns the full version number written to converted documents,
+// or an empty string if version is not supported
+func openAPIVersion(version string) string {
+	switch version {
+	case "3", "3.0", "3.0.3":
+		return "3.0.3"
+	case "3.1", "3.1.0":
+		return "3.1.0"
+	}
+	return ""
+}
+
+// Keywords shared by the schemas of Swagger 2.0 parameters, headers and items
+var simpleSchemaKeys = []string{
+	"type", "format", "items", "default", "enum", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "multipleOf",
+}
+
+// upgrader converts Swagger 2.0 documents to OpenAPI 3
+type upgrader struct {
+	version  string
+	v31      bool
+	consumes []string
+	produces []string
+	// Global body and formData parameters, inlined where they are referenced
+	inline map[string]map[string]interface{}
+}
+
+// convertToOpenAPI3 converts a Swagger 2.0 JSON document to the given OpenAPI 3 version.
+// Documents which are not Swagger 2.0 are returned unchanged.
+func convertToOpenAPI3(doc []byte, version string) ([]byte, error) {
+	var src map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	if err := dec.Decode(&src); err != nil {
+		return nil, fmt.Errorf("invalid json document: %w", err)
+	}
+	if v, _ := src["swagger"].(string); !strings.HasPrefix(v, "2.") {
+		return doc, nil
+	}
+
+	u := &upgrader{
+		version:  openAPIVersion(version),
+		consumes: stringSlice(src["consumes"]),
+		produces: stringSlice(src["produces"]),
+		inline:   make(map[string]map[string]interface{}),
+	}
+	u.v31 = strings.HasPrefix(u.version, "3.1")
+	if len(u.consumes) == 0 {
+		u.consumes = []string{"application/json"}
+	}
+	if len(u.produces) == 0 {
+		u.produces = []string{"application/json"}
+	}
+
+	out := map[string]interface{}{"openapi": u.version}
+	for k, v := range src {
+		switch k {
+		case "info", "tags", "security", "externalDocs":
+			out[k] = v
+		default:
+			if strings.HasPrefix(k, "x-") {
+				out[k] = v
+			}
+		}
+	}
+
+	if servers := u.servers(src); len(servers) > 0 {
+		out["servers"] = servers
+	}
+
+	components := make(map[string]interface{})
+	if definitions, ok := src["definitions"].(map[string]interface{}); ok {
+		schemas := make(map[string]interface{}, len(definitions))
+		for name, schema := range definitions {
+			schemas[name] = u.schema(schema)
+		}
+		components["schemas"] = schemas
+	}
+	if params, ok := src["parameters"].(map[string]interface{}); ok {
+		converted := make(map[string]interface{})
+		for name, raw := range params {
+			param, _ := raw.(map[string]interface{})
+			if in, _ := param["in"].(string); in == "body" || in == "formData" {
+				u.inline["#/parameters/"+escapePointer(name)] = param
+				continue
+			}
+			converted[name] = u.parameter(param)
+		}
+		if len(converted) > 0 {
+			components["parameters"] = converted
+		}
+	}
+	if responses, ok := src["responses"].(map[string]interface{}); ok {
+		converted := make(map[string]interface{}, len(responses))
+		for name, response := range responses {
+			converted[name] = u.response(response, u.produces)
+		}
+		components["responses"] = converted
+	}
+	if definitions, ok := src["securityDefinitions"].(map[string]interface{}); ok {
+		schemes := make(map[string]interface{}, len(definitions))
+		for name, definition := range definitions {
+			schemes[name] = securityScheme(definition)
+		}
+		components["securitySchemes"] = schemes
+	}
+
+	paths := make(map[string]interface{})
+	if srcPaths, ok := src["paths"].(map[string]interface{}); ok {
+		for p, item := range srcPaths {
+			paths[p] = u.pathItem(item)
+		}
+	}
+	out["paths"] = paths
+	if len(components) > 0 {
+		out["components"] = components
+	}
+
+	// Remaining references follow the new layout
+	rewriteRefs(out, false)
+
+	return marshalJSON(out)
+}
+
+// servers builds the servers of the document from its host, basePath and schemes
+func (u *upgrader) servers(src map[string]interface{}) []interface{} {
+	host, _ := src["host"].(string)
+	basePath, _ := src["basePath"].(string)
+	if host == "" && basePath == "" {
+		return nil
+	}
+	if basePath == "" {
+		basePath = "/"
+	}
+	if host == "" {
+		return []interface{}{map[string]interface{}{"url": basePath}}
+	}
+
+	schemes := stringSlice(src["schemes"])
+	if len(schemes) == 0 {
+		// Same scheme as the document
+		return []interface{}{map[string]interface{}{"url": "//" + host + basePath}}
+	}
+	servers := make([]interface{}, 0, len(schemes))
+	for _, scheme := range schemes {
+		servers = append(servers, map[string]interface{}{"url": scheme + "://" + host + basePath})
+	}
+	return servers
+}
+
+func (u *upgrader) pathItem(raw interface{}) interface{} {
+	item, ok := raw.(map[string]interface{})
+	if !ok {
+		return raw
+	}
+
+	out := make(map[string]interface{})
+	// Body and form parameters of the path item move to the request body of its operations
+	var shared []interface{}
+	if params, ok := item["parameters"].([]interface{}); ok {
+		var converted []interface{}
+		for _, raw := range params {
+			param := u.resolve(raw)
+			if in, _ := param["in"].(string); in == "body" || in == "formData" {
+				shared = append(shared, param)
+				continue
+			}
+			converted = append(converted, u.parameter(param))
+		}
+		if len(converted) > 0 {
+			out["parameters"] = converted
+		}
+	}
+
+	for k, v := range item {
+		switch {
+		case k == "parameters":
+		case containsString(operationMethods, k):
+			out[k] = u.operation(v, shared)
+		default:
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func (u *upgrader) operation(raw interface{}, shared []interface{}) interface{} {
+	op, ok := raw.(map[string]interface{})
+	if !ok {
+		return raw
+	}
+
+	consumes, produces := u.consumes, u.produces
+	if c := stringSlice(op["consumes"]); len(c) > 0 {
+		consumes = c
+	}
+	if p := stringSlice(op["produces"]); len(p) > 0 {
+		produces = p
+	}
+
+	out := make(map[string]interface{})
+	for k, v := range op {
+		switch k {
+		case "consumes", "produces", "schemes", "parameters", "responses":
+		default:
+			out[k] = v
+		}
+	}
+
+	params, _ := op["parameters"].([]interface{})
+	var (
+		converted []interface{}
+		body      map[string]interface{}
+		form      []map[string]interface{}
+	)
+	for _, p := range append(append([]interface{}(nil), shared...), params...) {
+		param := u.resolve(p)
+		switch param["in"] {
+		case "body":
+			body = param
+		case "formData":
+			form = append(form, param)
+		default:
+			converted = append(converted, u.parameter(param))
+		}
+	}
+	if len(converted) > 0 {
+		out["parameters"] = converted
+	}
+
+	switch {
+	case body != nil:
+		out["requestBody"] = u.requestBody(body, consumes)
+	case len(form) > 0:
+		out["requestBody"] = u.formBody(form, consumes)
+	}
+
+	if responses, ok := op["responses"].(map[string]interface{}); ok {
+		converted := make(map[string]interface{}, len(responses))
+		for code, response := range responses {
+			converted[code] = u.response(response, produces)
+		}
+		out["responses"] = converted
+	}
+
+	return out
+}
+
+// resolve returns the parameter, inlining references to global body and form parameters
+func (u *upgrader) resolve(raw interface{}) map[string]interface{} {
+	param, _ := raw.(map[string]interface{})
+	if ref, ok := param["$ref"].(string); ok {
+		if target, ok := u.inline[ref]; ok {
+			return target
+		}
+	}
+	return param
+}
+
+// parameter converts a path, query, header or cookie parameter
+func (u *upgrader) parameter(param map[string]interface{}) map[string]interface{} {
+	if _, ok := param["$ref"]; ok {
+		return param
+	}
+
+	out := make(map[string]interface{})
+	schema := make(map[string]interface{})
+	for k, v := range param {
+		switch {
+		case containsString(simpleSchemaKeys, k):
+			schema[k] = v
+		case k == "collectionFormat", k == "allowEmptyValue" && param["in"] != "query":
+		case k == "x-nullable":
+			schema[k] = v
+		default:
+			out[k] = v
+		}
+	}
+	out["schema"] = u.schema(schema)
+
+	if param["type"] == "array" {
+		in, _ := param["in"].(string)
+		switch param["collectionFormat"] {
+		case "multi":
+			out["style"], out["explode"] = "form", true
+		case "ssv":
+			out["style"], out["explode"] = "spaceDelimited", false
+		case "pipes":
+			out["style"], out["explode"] = "pipeDelimited", false
+		default:
+			if in == "query" || in == "cookie" {
+				out["style"], out["explode"] = "form", false
+			}
+		}
+	}
+	return out
+}
+
+func (u *upgrader) requestBody(param map[string]interface{}, consumes []string) map[string]interface{} {
+	out := map[string]interface{}{"content": content(u.schema(param["schema"]), consumes)}
+	if description, ok := param["description"]; ok {
+		out["description"] = description
+	}
+	if required, ok := param["required"]; ok {
+		out["required"] = required
+	}
+	if name, ok := param["name"]; ok {
+		out["x-codegen-request-body-name"] = name
+	}
+	return out
+}
+
+func (u *upgrader) formBody(params []map[string]interface{}, consumes []string) map[string]interface{} {
+	properties := make(map[string]interface{}, len(params))
+	var required []interface{}
+	hasFile := false
+	for _, param := range params {
+		name, _ := param["name"].(string)
+		schema := make(map[string]interface{})
+		for _, k := range simpleSchemaKeys {
+			if v, ok := param[k]; ok {
+				schema[k] = v
+			}
+		}
+		if description, ok := param["description"]; ok {
+			schema["description"] = description
+		}
+		if param["type"] == "file" {
+			hasFile = true
+		}
+		properties[name] = u.schema(schema)
+		if r, _ := param["required"].(bool); r {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	var mimes []string
+	for _, mime := range consumes {
+		if mime == "multipart/form-data" || mime == "application/x-www-form-urlencoded" {
+			mimes = append(mimes, mime)
+		}
+	}
+	if len(mimes) == 0 {
+		mimes = []string{"application/x-www-form-urlencoded"}
+		if hasFile {
+			mimes = []string{"multipart/form-data"}
+		}
+	}
+	return map[string]interface{}{"content": content(schema, mimes)}
+}
+
+func (u *upgrader) response(raw interface{}, produces []string) interface{} {
+	response, ok := raw.(map[string]interface{})
+	if !ok {
+		return raw
+	}
+	if _, ok := response["$ref"]; ok {
+		return response
+	}
+
+	out := make(map[string]interface{})
+	for k, v := range response {
+		switch k {
+		case "schema", "examples", "headers":
+		default:
+			out[k] = v
+		}
+	}
+	if _, ok := out["description"]; !ok {
+		out["description"] = ""
+	}
+
+	if headers, ok := response["headers"].(map[string]interface{}); ok {
+		converted := make(map[string]interface{}, len(headers))
+		for name, raw := range headers {
+			header, _ := raw.(map[string]interface{})
+			h := make(map[string]interface{})
+			schema := make(map[string]interface{})
+			for k, v := range header {
+				if containsString(simpleSchemaKeys, k) {
+					schema[k] = v
+				} else if k != "collectionFormat" {
+					h[k] = v
+				}
+			}
+			h["schema"] = u.schema(schema)
+			converted[name] = h
+		}
+		out["headers"] = converted
+	}
+
+	if schema, ok := response["schema"]; ok {
+		c := content(u.schema(schema), produces)
+		if examples, ok := response["examples"].(map[string]interface{}); ok {
+			for mime, example := range examples {
+				if media, ok := c[mime].(map[string]interface{}); ok {
+					media["example"] = example
+				}
+			}
+		}
+		out["content"] = c
+	}
+	return out
+}
+
+// schema converts the keywords of a Swagger 2.0 schema which changed in OpenAPI 3
+func (u *upgrader) schema(raw interface{}) interface{} {
+	schema, ok := raw.(map[string]interface{})
+	if !ok {
+		return raw
+	}
+
+	out := make(map[string]interface{}, len(schema))
+	for k, v := range schema {
+		switch k {
+		case "properties", "definitions", "patternProperties":
+			if m, ok := v.(map[string]interface{}); ok {
+				converted := make(map[string]interface{}, len(m))
+				for name, s := range m {
+					converted[name] = u.schema(s)
+				}
+				v = converted
+			}
+		case "items", "additionalProperties", "not":
+			v = u.schema(v)
+		case "allOf", "anyOf", "oneOf":
+			if list, ok := v.([]interface{}); ok {
+				converted := make([]interface{}, len(list))
+				for i, s := range list {
+					converted[i] = u.schema(s)
+				}
+				v = converted
+			}
+		case "discriminator":
+			if name, ok := v.(string); ok {
+				v = map[string]interface{}{"propertyName": name}
+			}
+		case "x-example":
+			k = "example"
+		}
+		out[k] = v
+	}
+
+	if out["type"] == "file" {
+		out["type"], out["format"] = "string", "binary"
+	}
+
+	if nullable, ok := out["x-nullable"].(bool); ok {
+		delete(out, "x-nullable")
+		if nullable {
+			out["nullable"] = true
+		}
+	}
+
+	if u.v31 {
+		// JSON Schema 2020-12 replaces nullable and the boolean exclusive bounds
+		if nullable, _ := out["nullable"].(bool); nullable {
+			if t, ok := out["type"].(string); ok {
+				out["type"] = []interface{}{t, "null"}
+			}
+		}
+		delete(out, "nullable")
+		for bound, limit := range map[string]string{"exclusiveMinimum": "minimum", "exclusiveMaximum": "maximum"} {
+			if exclusive, ok := out[bound].(bool); ok {
+				delete(out, bound)
+				// A boolean bound without its limit has no meaning and is dropped
+				if value, hasLimit := out[limit]; exclusive && hasLimit {
+					out[bound] = value
+					delete(out, limit)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// securityScheme converts a security definition
+func securityScheme(raw interface{}) interface{} {
+	definition, ok := raw.(map[string]interface{})
+	if !ok {
+		return raw
+	}
+
+	out := make(map[string]interface{})
+	for k, v := range definition {
+		if k == "description" || strings.HasPrefix(k, "x-") {
+			out[k] = v
+		}
+	}
+
+	switch definition["type"] {
+	case "basic":
+		out["type"], out["scheme"] = "http", "basic"
+	case "apiKey":
+		out["type"], out["name"], out["in"] = "apiKey", definition["name"], definition["in"]
+	case "oauth2":
+		flow := map[string]interface{}{"scopes": definition["scopes"]}
+		if flow["scopes"] == nil {
+			flow["scopes"] = map[string]interface{}{}
+		}
+		var name string
+		switch definition["flow"] {
+		case "implicit":
+			name = "implicit"
+			flow["authorizationUrl"] = definition["authorizationUrl"]
+		case "password":
+			name = "password"
+			flow["tokenUrl"] = definition["tokenUrl"]
+		case "application":
+			name = "clientCredentials"
+			flow["tokenUrl"] = definition["tokenUrl"]
+		case "accessCode":
+			name = "authorizationCode"
+			flow["authorizationUrl"] = definition["authorizationUrl"]
+			flow["tokenUrl"] = definition["tokenUrl"]
+		}
+		out["type"] = "oauth2"
+		out["flows"] = map[string]interface{}{name: flow}
+	default:
+		out["type"] = definition["type"]
+	}
+	return out
+}
+
+// content returns a content map with schema for each of the media types
+func content(schema interface{}, mimes []string) map[string]interface{} {
+	c := make(map[string]interface{}, len(mimes))
+	for _, mime := range mimes {
+		c[mime] = map[string]interface{}{"schema": schema}
+	}
+	return c
+}
+
+// rewriteRefs points the references of v to the sections of OpenAPI 3 components. names tells whether
+// the keys of v are names, e.g. of properties or responses, rather than keywords.
+func rewriteRefs(v interface{}, names bool) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, child := range t {
+			if !names {
+				// Literal values may hold "$ref" keys which are not references
+				if isLiteralKeyword(k) {
+					continue
+				}
+				if ref, ok := child.(string); ok && k == "$ref" {
+					for _, section := range [][2]string{
+						{"#/definitions/", "#/components/schemas/"},
+						{"#/parameters/", "#/components/parameters/"},
+						{"#/responses/", "#/components/responses/"},
+					} {
+						if strings.HasPrefix(ref, section[0]) {
+							t[k] = section[1] + strings.TrimPrefix(ref, section[0])
+						}
+					}
+					continue
+				}
+			}
+			rewriteRefs(child, !names && isNamedSection(k))
+		}
+	case []interface{}:
+		for _, child := range t {
+			rewriteRefs(child, false)
+		}
+	}
+}
+
+// isNamedSection reports whether the keys of the object under the key k are names rather than keywords
+func isNamedSection(k string) bool {
+	switch k {
+	case "paths", "properties", "patternProperties", "schemas", "parameters", "responses", "requestBodies",
+		"headers", "securitySchemes", "callbacks", "links", "content":
+		return true
+	}
+	return false
+}
+
+// isLiteralKeyword reports whether the values under the key k are data rather than parts of the document
+func isLiteralKeyword(k string) bool {
+	switch k {
+	case "example", "examples", "default", "enum", "const":
+		return true
+	}
+	return strings.HasPrefix(k, "x-")
+}
@@ -0,0 +1,220 @@
+package swagger
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const upgradeSwaggerDoc = `{
+	"swagger": "2.0",
+	"info": {"title": "Petstore", "version": "1.0"},
+	"host": "petstore.swagger.io",
+	"basePath": "/v2",
+	"schemes": ["https"],
+	"consumes": ["application/json"],
+	"produces": ["application/json", "application/xml"],
+	"paths": {
+		"/pets/{id}": {
+			"parameters": [{"name": "id", "in": "path", "required": true, "type": "integer", "format": "int64"}],
+			"get": {
+				"parameters": [{"name": "tags", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi"}],
+				"responses": {
+					"200": {"description": "ok", "schema": {"$ref": "#/definitions/Pet"}, "headers": {"X-Rate": {"type": "integer"}}},
+					"404": {"$ref": "#/responses/NotFound"}
+				}
+			},
+			"put": {
+				"parameters": [{"$ref": "#/parameters/PetBody"}],
+				"responses": {"204": {"description": "updated"}}
+			},
+			"post": {
+				"consumes": ["multipart/form-data"],
+				"parameters": [{"name": "file", "in": "formData", "type": "file", "required": true}],
+				"responses": {"200": {"description": "uploaded"}}
+			}
+		}
+	},
+	"parameters": {"PetBody": {"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}},
+	"responses": {"NotFound": {"description": "not found"}},
+	"definitions": {
+		"Pet": {"type": "object", "discriminator": "kind", "properties": {"name": {"type": "string", "x-nullable": true}}}
+	},
+	"securityDefinitions": {
+		"basic": {"type": "basic"},
+		"oauth": {"type": "oauth2", "flow": "accessCode", "authorizationUrl": "https://a", "tokenUrl": "https://t", "scopes": {"read": "Read"}}
+	}
+}`
+
+func Test_Convert_To_OpenAPI3(t *testing.T) {
+	out, err := convertToOpenAPI3([]byte(upgradeSwaggerDoc), "3.0.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		pointer  string
+		expected string
+	}{
+		{"/openapi", `"3.0.3"`},
+		{"/servers", `[{"url":"https://petstore.swagger.io/v2"}]`},
+		{"/components/schemas/Pet", `{"discriminator":{"propertyName":"kind"},"properties":{"name":{"nullable":true,"type":"string"}},"type":"object"}`},
+		{"/components/responses/NotFound", `{"description":"not found"}`},
+		{"/components/securitySchemes/basic", `{"scheme":"basic","type":"http"}`},
+		{"/components/securitySchemes/oauth", `{"flows":{"authorizationCode":{"authorizationUrl":"https://a","scopes":{"read":"Read"},"tokenUrl":"https://t"}},"type":"oauth2"}`},
+		{"/paths/~1pets~1{id}/parameters", `[{"in":"path","name":"id","required":true,"schema":{"format":"int64","type":"integer"}}]`},
+		{"/paths/~1pets~1{id}/get/parameters", `[{"explode":true,"in":"query","name":"tags","schema":{"items":{"type":"string"},"type":"array"},"style":"form"}]`},
+		{"/paths/~1pets~1{id}/get/responses/200", `{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Pet"}},` +
+			`"application/xml":{"schema":{"$ref":"#/components/schemas/Pet"}}},"description":"ok","headers":{"X-Rate":{"schema":{"type":"integer"}}}}`},
+		{"/paths/~1pets~1{id}/get/responses/404", `{"$ref":"#/components/responses/NotFound"}`},
+		{"/paths/~1pets~1{id}/put/requestBody", `{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Pet"}}},"required":true,"x-codegen-request-body-name":"pet"}`},
+		{"/paths/~1pets~1{id}/post/requestBody", `{"content":{"multipart/form-data":{"schema":{"properties":{"file":{"format":"binary","type":"string"}},"required":["file"],"type":"object"}}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			var v interface{} = doc
+			for _, token := range strings.Split(tt.pointer, "/")[1:] {
+				v = v.(map[string]interface{})[unescapePointer(token)]
+			}
+
+			var expected interface{}
+			if err := json.Unmarshal([]byte(tt.expected), &expected); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(v, expected) {
+				got, _ := json.Marshal(v)
+				t.Fatalf("got %s - expected %s", got, tt.expected)
+			}
+		})
+	}
+
+	if _, ok := doc["components"].(map[string]interface{})["parameters"]; ok {
+		t.Fatal("body parameters should be inlined")
+	}
+}
+
+func Test_Convert_To_OpenAPI31(t *testing.T) {
+	doc := `{"swagger":"2.0","definitions":{"N":{"type":"integer","x-nullable":true,"minimum":0,"exclusiveMinimum":true}}}`
+	out, err := convertToOpenAPI3([]byte(doc), "3.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"components":{"schemas":{"N":{"exclusiveMinimum":0,"type":["integer","null"]}}},"openapi":"3.1.0","paths":{}}`
+	if string(out) != expected {
+		t.Fatalf("got %s - expected %s", out, expected)
+	}
+}
+
+func Test_Convert_To_OpenAPI31_Bounds(t *testing.T) {
+	doc := `{"swagger":"2.0","definitions":{"N":{"type":"integer","exclusiveMinimum":true,"maximum":9,"exclusiveMaximum":false}}}`
+	out, err := convertToOpenAPI3([]byte(doc), "3.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"components":{"schemas":{"N":{"maximum":9,"type":"integer"}}},"openapi":"3.1.0","paths":{}}`
+	if string(out) != expected {
+		t.Fatalf("got %s - expected %s", out, expected)
+	}
+}
+
+func Test_Convert_To_OpenAPI3_Literal_Refs(t *testing.T) {
+	doc := `{"swagger":"2.0","definitions":{"Ref":{"type":"object","properties":{` +
+		`"default":{"$ref":"#/definitions/Ref"},"link":{"type":"object","example":{"$ref":"#/definitions/Ref"}}},` +
+		`"x-sample":{"$ref":"#/definitions/Ref"}}}}`
+	out, err := convertToOpenAPI3([]byte(doc), "3.0.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		`"default":{"$ref":"#/components/schemas/Ref"}`,
+		`"example":{"$ref":"#/definitions/Ref"}`,
+		`"x-sample":{"$ref":"#/definitions/Ref"}`,
+	} {
+		if !strings.Contains(string(out), expected) {
+			t.Fatalf("got %s - expected to contain %s", out, expected)
+		}
+	}
+}
+
+func Test_Swagger_Output_Version(t *testing.T) {
+	get := func(t *testing.T, app *fiber.App, url string) (*http.Response, string) {
+		t.Helper()
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp, string(body)
+	}
+
+	t.Run("Should serve the converted document", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/swag/*", New(Config{
+			Spec:          BytesSpec([]byte("swagger: \"2.0\"\nhost: example.com\n")),
+			OutputVersion: "3.1",
+		}))
+
+		resp, body := get(t, app, "/swag/openapi.json")
+		if resp.StatusCode != 200 {
+			t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 200)
+		}
+		expected := `{"openapi":"3.1.0","paths":{},"servers":[{"url":"//example.com/"}]}`
+		if body != expected {
+			t.Fatalf("Body: got %s - expected %s", body, expected)
+		}
+
+		_, body = get(t, app, "/swag/openapi.yaml")
+		if !strings.HasPrefix(body, "openapi: 3.1.0\n") {
+			t.Fatalf("Body: got %s - expected an OpenAPI 3.1 document", body)
+		}
+
+		_, body = get(t, app, "/swag/doc.json")
+		if body != `{"swagger":"2.0","host":"example.com"}` {
+			t.Fatalf("Body: got %s - expected the original document", body)
+		}
+	})
+
+	t.Run("Should not serve openapi.json without an output version", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/swag/*", New(Config{Spec: BytesSpec([]byte(`{"swagger":"2.0"}`))}))
+
+		resp, _ := get(t, app, "/swag/openapi.json")
+		if resp.StatusCode != 404 {
+			t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 404)
+		}
+	})
+
+	t.Run("Should reject unsupported versions", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected a panic")
+			}
+		}()
+
+		New(Config{OutputVersion: "4.0"})
+	})
+}
@@ -0,0 +1,160 @@
+package swagger
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// Directory "Try it out" requests are proxied under, followed by "<scheme>/<host>/<path>"
+const proxyDir = "_proxy/"
+
+const defaultProxyTimeout = 10 * time.Second
+
+// Headers only meaningful for a single connection, never forwarded
+var hopHeaders = []string{
+	"Connection", "Keep-Alive", "Proxy-Authenticate", "Proxy-Authorization", "Proxy-Connection",
+	"TE", "Trailer", "Transfer-Encoding", "Upgrade",
+}
+
+// ProxyConfig configures the proxy "Try it out" requests are sent through, so that the API does not need
+// to allow the documentation host with CORS. Requests are forwarded to the allowed hosts only.
+type ProxyConfig struct {
+	// Hosts requests may be forwarded to, e.g. "api.example.com", "api.example.com:8443" or "*.example.com".
+	// Entries without a port match any port.
+	// default: nil -> required
+	AllowedHosts []string
+
+	// Request headers removed before forwarding, in addition to the hop-by-hop headers.
+	// The Authorization header is removed as well when it carries the credentials BasicAuth or BearerAuth
+	// authorized the request with.
+	// default: ["Cookie"]
+	StripHeaders []string
+
+	// Response headers removed before answering, in addition to the hop-by-hop headers.
+	// default: ["Set-Cookie"]
+	StripResponseHeaders []string
+
+	// Maximum duration of an upstream request, answered with 504 Gateway Timeout when exceeded.
+	// default: 10s
+	Timeout time.Duration
+}
+
+// proxy forwards the "Try it out" requests of Swagger UI
+type proxy struct {
+	hosts         []string
+	stripHeaders  []string
+	stripResponse []string
+	timeout       time.Duration
+	client        *fasthttp.Client
+}
+
+func newProxy(pc *ProxyConfig) (*proxy, error) {
+	if len(pc.AllowedHosts) == 0 {
+		return nil, errors.New("fiber: swagger middleware error -> Proxy requires AllowedHosts")
+	}
+
+	p := &proxy{
+		stripHeaders:  pc.StripHeaders,
+		stripResponse: pc.StripResponseHeaders,
+		timeout:       pc.Timeout,
+	}
+	for _, h := range pc.AllowedHosts {
+		p.hosts = append(p.hosts, strings.ToLower(h))
+	}
+	if p.stripHeaders == nil {
+		p.stripHeaders = []string{fiber.HeaderCookie}
+	}
+	if p.stripResponse == nil {
+		p.stripResponse = []string{fiber.HeaderSetCookie}
+	}
+	if p.timeout <= 0 {
+		p.timeout = defaultProxyTimeout
+	}
+	p.client = &fasthttp.Client{
+		ReadTimeout:              p.timeout,
+		WriteTimeout:             p.timeout,
+		NoDefaultUserAgentHeader: true,
+		DisablePathNormalizing:   true,
+	}
+	return p, nil
+}
+
+// allowed reports whether requests may be forwarded to host, given as "name" or "name:port"
+func (p *proxy) allowed(host string) bool {
+	host = strings.ToLower(host)
+	name := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		name = h
+	}
+
+	for _, pattern := range p.hosts {
+		candidate := name
+		if _, _, err := net.SplitHostPort(pattern); err == nil {
+			candidate = host
+		}
+		if pattern == candidate {
+			return true
+		}
+		if strings.HasPrefix(pattern, "*.") && strings.HasSuffix(candidate, pattern[1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// forward sends the request to target, given as "<scheme>/<host>/<path>", and answers with the upstream response
+func (p *proxy) forward(c *fiber.Ctx, target string) error {
+	scheme, rest, _ := strings.Cut(target, "/")
+	host, path, _ := strings.Cut(rest, "/")
+	if scheme != "http" && scheme != "https" || host == "" {
+		return fiber.ErrNotFound
+	}
+	if !p.allowed(host) {
+		return fiber.NewError(fiber.StatusForbidden, "host "+host+" is not allowed")
+	}
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	c.Request().CopyTo(req)
+	uri := scheme + "://" + host + "/" + path
+	if q := c.Request().URI().QueryString(); len(q) > 0 {
+		uri += "?" + string(q)
+	}
+	req.SetRequestURI(uri)
+	req.Header.SetHost(host)
+	for _, h := range hopHeaders {
+		req.Header.Del(h)
+	}
+	for _, h := range p.stripHeaders {
+		req.Header.Del(h)
+	}
+	// Browsers send the cached documentation credentials along, unless the request has its own
+	if creds, ok := c.Locals(credentialsKey{}).(string); ok && string(req.Header.Peek(fiber.HeaderAuthorization)) == creds {
+		req.Header.Del(fiber.HeaderAuthorization)
+	}
+
+	if err := p.client.DoTimeout(req, resp, p.timeout); err != nil {
+		if errors.Is(err, fasthttp.ErrTimeout) {
+			return fiber.ErrGatewayTimeout
+		}
+		return fiber.NewError(fiber.StatusBadGateway, utils.StatusMessage(fiber.StatusBadGateway)+": "+err.Error())
+	}
+
+	resp.CopyTo(c.Response())
+	for _, h := range hopHeaders {
+		c.Response().Header.Del(h)
+	}
+	for _, h := range p.stripResponse {
+		c.Response().Header.Del(h)
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package swagger
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_Proxy(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=upstream")
+		w.Header().Set("X-Upstream", "1")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, r.Method+" "+r.Host+" "+r.URL.RequestURI()+" cookie="+r.Header.Get("Cookie")+
+			" auth="+r.Header.Get("Authorization")+" body="+string(body))
+	}))
+	defer upstream.Close()
+	host := strings.TrimPrefix(upstream.URL, "http://")
+
+	app := fiber.New()
+	app.All("/swag/*", New(Config{
+		Spec: BytesSpec([]byte(`{"swagger":"2.0"}`)),
+		Proxy: &ProxyConfig{
+			AllowedHosts: []string{"127.0.0.1"},
+			Timeout:      50 * time.Millisecond,
+		},
+	}))
+
+	tests := []struct {
+		name       string
+		method     string
+		url        string
+		body       string
+		statusCode int
+		expected   string
+	}{
+		{
+			name:       "Forwarded request",
+			method:     http.MethodPost,
+			url:        "/swag/_proxy/http/" + host + "/v1/users?limit=1",
+			body:       `{"name":"Ada"}`,
+			statusCode: 201,
+			expected:   "POST " + host + ` /v1/users?limit=1 cookie= auth=Bearer token body={"name":"Ada"}`,
+		},
+		{
+			name:       "Host not allowed",
+			method:     http.MethodGet,
+			url:        "/swag/_proxy/http/example.com/v1/users",
+			statusCode: 403,
+			expected:   "host example.com is not allowed",
+		},
+		{
+			name:       "Unsupported scheme",
+			method:     http.MethodGet,
+			url:        "/swag/_proxy/ftp/" + host + "/file",
+			statusCode: 404,
+		},
+		{
+			name:       "Upstream timeout",
+			method:     http.MethodGet,
+			url:        "/swag/_proxy/http/" + host + "/slow",
+			statusCode: 504,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Cookie", "session=docs")
+			req.Header.Set("Authorization", "Bearer token")
+
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tt.statusCode {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, tt.statusCode)
+			}
+			if resp.Header.Get("Set-Cookie") != "" {
+				t.Fatalf("Set-Cookie header was forwarded: %s", resp.Header.Get("Set-Cookie"))
+			}
+			if tt.expected == "" {
+				return
+			}
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(body) != tt.expected {
+				t.Fatalf("Body: got %s - expected %s", body, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_Proxy_Authorizer(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "auth="+r.Header.Get("Authorization"))
+	}))
+	defer upstream.Close()
+	host := strings.TrimPrefix(upstream.URL, "http://")
+
+	tests := []struct {
+		name          string
+		authorizer    func(*fiber.Ctx) (bool, error)
+		authorization string
+		expected      string
+	}{
+		{
+			name:          "Should strip the BasicAuth credentials",
+			authorizer:    BasicAuth("docs", map[string]string{"admin": "secret"}),
+			authorization: "Basic YWRtaW46c2VjcmV0",
+			expected:      "auth=",
+		},
+		{
+			name:          "Should strip the BearerAuth token",
+			authorizer:    BearerAuth("docs-token"),
+			authorization: "Bearer docs-token",
+			expected:      "auth=",
+		},
+		{
+			name:          "Should forward a Bearer token with IPAllowlist",
+			authorizer:    IPAllowlist("0.0.0.0/0", "::/0"),
+			authorization: "Bearer api-token",
+			expected:      "auth=Bearer api-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.All("/swag/*", New(Config{
+				Spec:       BytesSpec([]byte(`{"swagger":"2.0"}`)),
+				Authorizer: tt.authorizer,
+				Proxy:      &ProxyConfig{AllowedHosts: []string{"127.0.0.1"}},
+			}))
+
+			req, err := http.NewRequest(http.MethodGet, "/swag/_proxy/http/"+host+"/v1/users", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Authorization", tt.authorization)
+
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != 200 {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 200)
+			}
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(body) != tt.expected {
+				t.Fatalf("Body: got %s - expected %s", body, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_Proxy_Allowed_Hosts(t *testing.T) {
+	p, err := newProxy(&ProxyConfig{AllowedHosts: []string{"api.example.com", "*.internal.example.com", "Localhost:8080"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		host    string
+		allowed bool
+	}{
+		{host: "api.example.com", allowed: true},
+		{host: "API.example.com:8443", allowed: true},
+		{host: "evil-api.example.com", allowed: false},
+		{host: "users.internal.example.com", allowed: true},
+		{host: "internal.example.com", allowed: false},
+		{host: "localhost:8080", allowed: true},
+		{host: "localhost:9090", allowed: false},
+		{host: "localhost", allowed: false},
+	}
+
+	for _, tt := range tests {
+		if allowed := p.allowed(tt.host); allowed != tt.allowed {
+			t.Fatalf("allowed(%s): got %v - expected %v", tt.host, allowed, tt.allowed)
+		}
+	}
+
+	if _, err := newProxy(&ProxyConfig{}); err == nil {
+		t.Fatal("expected an error without AllowedHosts")
+	}
+}
+
+func Test_Proxy_Request_Interceptor(t *testing.T) {
+	app := fiber.New()
+	app.Get("/swag/*", New(Config{
+		Spec:               BytesSpec([]byte(`{"swagger":"2.0"}`)),
+		RequestInterceptor: "(req) => req",
+		Proxy:              &ProxyConfig{AllowedHosts: []string{"api.example.com"}},
+	}))
+
+	req, err := http.NewRequest(http.MethodGet, "/swag/index.html", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"new URL('./_proxy/', window.location.href)",
+		"const requestInterceptor = (req) => req;",
+		"Promise.resolve(requestInterceptor(request)).then(proxyRequest)",
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Fatalf("index.html does not contain %s", expected)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package swagger
+
+// Renderer is a documentation viewer used instead of Swagger UI.
+// The available renderers are RedocConfig, RapiDocConfig, ScalarConfig and ElementsConfig.
+type Renderer interface {
+	// template returns the HTML shell of the viewer. It is executed with a pageData.
+	template() string
+
+	// assets returns the urls of the script and the stylesheet of the viewer, defaults applied.
+	assets() (script, style string)
+
+	// integrity returns the Subresource Integrity hashes of the script and the stylesheet, if any.
+	integrity() (script, style string)
+}
+
+// pageData is passed to the template of a Renderer
+type pageData struct {
+	Title           string
+	URL             string
+	ScriptURL       string
+	StyleURL        string
+	ScriptIntegrity string
+	StyleIntegrity  string
+	Renderer        Renderer
+}
+
+// Default locations of the renderer assets
+const (
+	redocScriptURL    = "https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js"
+	rapiDocScriptURL  = "https://unpkg.com/rapidoc@9.3.8/dist/rapidoc-min.js"
+	scalarScriptURL   = "https://cdn.jsdelivr.net/npm/@scalar/api-reference@1.25.0"
+	elementsScriptURL = "https://unpkg.com/@stoplight/elements@8.0.0/web-components.min.js"
+	elementsStyleURL  = "https://unpkg.com/@stoplight/elements@8.0.0/styles.min.css"
+)
+
+// orDefault returns s, or def if s is empty
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// RedocConfig configures the Redoc viewer, see https://redocly.com/docs/redoc/config
+type RedocConfig struct {
+	// URL of the Redoc standalone bundle.
+	// default: "https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js"
+	ScriptURL string `json:"-"`
+
+	// Subresource Integrity hash of the bundle, e.g. "sha384-...".
+	// default: ""
+	ScriptIntegrity string `json:"-"`
+
+	// Disables the search box.
+	// default: false
+	DisableSearch bool `json:"disableSearch,omitempty"`
+
+	// Comma separated list of response codes to expand by default, or "all".
+	// default: ""
+	ExpandResponses string `json:"expandResponses,omitempty"`
+
+	// Hides the "Download" button for saving the API definition.
+	// default: false
+	HideDownloadButton bool `json:"hideDownloadButton,omitempty"`
+
+	// Hides the protocol and hostname in the operation definitions.
+	// default: false
+	HideHostname bool `json:"hideHostname,omitempty"`
+
+	// Hides the schema title next to the type.
+	// default: false
+	HideSchemaTitles bool `json:"hideSchemaTitles,omitempty"`
+
+	// Default expansion level of JSON payload samples.
+	// default: 2
+	JSONSampleExpandLevel int `json:"jsonSampleExpandLevel,omitempty"`
+
+	// Uses the native browser scrollbar instead of perfect-scroll.
+	// default: false
+	NativeScrollbars bool `json:"nativeScrollbars,omitempty"`
+
+	// Shows the path link and HTTP verb in the middle panel instead of the right one.
+	// default: false
+	PathInMiddlePanel bool `json:"pathInMiddlePanel,omitempty"`
+
+	// Shows required properties first, in the same order as in the required array.
+	// default: false
+	RequiredPropsFirst bool `json:"requiredPropsFirst,omitempty"`
+
+	// Sorts properties alphabetically.
+	// default: false
+	SortPropsAlphabetically bool `json:"sortPropsAlphabetically,omitempty"`
+
+	// Redoc theme overrides, e.g. {"colors": {"primary": {"main": "#00ACD7"}}}.
+	// default: nil
+	Theme map[string]interface{} `json:"theme,omitempty"`
+}
+
+func (RedocConfig) template() string {
+	return redocTmpl
+}
+
+func (rc RedocConfig) assets() (string, string) {
+	return orDefault(rc.ScriptURL, redocScriptURL), ""
+}
+
+func (rc RedocConfig) integrity() (string, string) {
+	return rc.ScriptIntegrity, ""
+}
+
+// RapiDocConfig configures the RapiDoc viewer, see https://rapidocweb.com/api.html
+type RapiDocConfig struct {
+	// URL of the RapiDoc bundle.
+	// default: "https://unpkg.com/rapidoc@9.3.8/dist/rapidoc-min.js"
+	ScriptURL string
+
+	// Subresource Integrity hash of the bundle, e.g. "sha384-...".
+	// default: ""
+	ScriptIntegrity string
+
+	// Color scheme, "light" or "dark".
+	// default: "light"
+	Theme string
+
+	// Rendering style, "read", "view" or "focused".
+	// default: "view"
+	RenderStyle string
+
+	// Layout of the operations, "row" or "column".
+	// default: "row"
+	Layout string
+
+	// Primary color of buttons, tabs and the like.
+	// default: ""
+	PrimaryColor string
+
+	// Text shown in the header.
+	// default: "" -> Title
+	HeadingText string
+
+	// Hides the header containing the spec url and the search box.
+	// default: false
+	HideHeader bool
+
+	// Disables "Try" for all operations.
+	// default: false
+	DisableTry bool
+
+	// Hides the authentication section.
+	// default: false
+	HideAuthentication bool
+}
+
+func (RapiDocConfig) template() string {
+	return rapiDocTmpl
+}
+
+func (rc RapiDocConfig) assets() (string, string) {
+	return orDefault(rc.ScriptURL, rapiDocScriptURL), ""
+}
+
+func (rc RapiDocConfig) integrity() (string, string) {
+	return rc.ScriptIntegrity, ""
+}
+
+// ScalarConfig configures the Scalar API reference, see https://github.com/scalar/scalar
+type ScalarConfig struct {
+	// URL of the Scalar API reference bundle.
+	// default: "https://cdn.jsdelivr.net/npm/@scalar/api-reference@1.25.0"
+	ScriptURL string `json:"-"`
+
+	// Subresource Integrity hash of the bundle, e.g. "sha384-...".
+	// default: ""
+	ScriptIntegrity string `json:"-"`
+
+	// Color theme, e.g. "default", "alternate", "moon", "purple", "solarized" or "none".
+	// default: ""
+	Theme string `json:"theme,omitempty"`
+
+	// Layout of the reference, "modern" or "classic".
+	// default: "modern"
+	Layout string `json:"layout,omitempty"`
+
+	// Starts in dark mode.
+	// default: false
+	DarkMode bool `json:"darkMode,omitempty"`
+
+	// Hides the models section.
+	// default: false
+	HideModels bool `json:"hideModels,omitempty"`
+
+	// Hides the button to download the API definition.
+	// default: false
+	HideDownloadButton bool `json:"hideDownloadButton,omitempty"`
+
+	// Key used with CTRL/CMD to open the search modal.
+	// default: "k"
+	SearchHotKey string `json:"searchHotKey,omitempty"`
+
+	// Additional CSS applied to the reference.
+	// default: ""
+	CustomCSS string `json:"customCss,omitempty"`
+}
+
+func (ScalarConfig) template() string {
+	return scalarTmpl
+}
+
+func (sc ScalarConfig) assets() (string, string) {
+	return orDefault(sc.ScriptURL, scalarScriptURL), ""
+}
+
+func (sc ScalarConfig) integrity() (string, string) {
+	return sc.ScriptIntegrity, ""
+}
+
+// ElementsConfig configures Stoplight Elements, see https://docs.stoplight.io/docs/elements
+type ElementsConfig struct {
+	// URL of the Elements web components bundle.
+	// default: "https://unpkg.com/@stoplight/elements@8.0.0/web-components.min.js"
+	ScriptURL string
+
+	// URL of the Elements stylesheet.
+	// default: "https://unpkg.com/@stoplight/elements@8.0.0/styles.min.css"
+	StyleURL string
+
+	// Subresource Integrity hash of the bundle, e.g. "sha384-...".
+	// default: ""
+	ScriptIntegrity string
+
+	// Subresource Integrity hash of the stylesheet.
+	// default: ""
+	StyleIntegrity string
+
+	// Layout of the page, "sidebar" or "stacked".
+	// default: "sidebar"
+	Layout string
+
+	// Router used for navigation, "hash", "history" or "memory".
+	// default: "hash"
+	Router string
+
+	// Hides the "Try It" panel.
+	// default: false
+	HideTryIt bool
+
+	// Hides the schemas in the table of contents.
+	// default: false
+	HideSchemas bool
+
+	// Hides the "Export" button.
+	// default: false
+	HideExport bool
+}
+
+func (ElementsConfig) template() string {
+	return elementsTmpl
+}
+
+func (ec ElementsConfig) assets() (string, string) {
+	return orDefault(ec.ScriptURL, elementsScriptURL), orDefault(ec.StyleURL, elementsStyleURL)
+}
+
+func (ec ElementsConfig) integrity() (string, string) {
+	return ec.ScriptIntegrity, ec.StyleIntegrity
+}
+
+const redocTmpl string = `
+<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>{{.Title}}</title>
+    <style{{with nonce}} nonce="{{.}}"{{end}}>
+      body { margin: 0; padding: 0; }
+    </style>
+  </head>
+  <body>
+    <div id="redoc-container"></div>
+    <script src="{{.ScriptURL}}"{{with .ScriptIntegrity}} integrity="{{.}}" crossorigin="anonymous"{{end}}></script>
+    <script{{with nonce}} nonce="{{.}}"{{end}}>
+      Redoc.init({{.URL}}, {{.Renderer}}, document.getElementById('redoc-container'));
+    </script>
+  </body>
+</html>
+`
+
+const rapiDocTmpl string = `
+<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>{{.Title}}</title>
+    <script type="module" src="{{.ScriptURL}}"{{with .ScriptIntegrity}} integrity="{{.}}" crossorigin="anonymous"{{end}}></script>
+  </head>
+  <body>
+    <rapi-doc
+      spec-url="{{.URL}}"
+      heading-text="{{or .Renderer.HeadingText .Title}}"
+      {{- with .Renderer.Theme}} theme="{{.}}"{{end}}
+      {{- with .Renderer.RenderStyle}} render-style="{{.}}"{{end}}
+      {{- with .Renderer.Layout}} layout="{{.}}"{{end}}
+      {{- with .Renderer.PrimaryColor}} primary-color="{{.}}"{{end}}
+      {{- if .Renderer.HideHeader}} show-header="false"{{end}}
+      {{- if .Renderer.DisableTry}} allow-try="false"{{end}}
+      {{- if .Renderer.HideAuthentication}} allow-authentication="false"{{end}}
+    ></rapi-doc>
+  </body>
+</html>
+`
+
+const scalarTmpl string = `
+<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>{{.Title}}</title>
+  </head>
+  <body>
+    <script id="api-reference" data-url="{{.URL}}"></script>
+    <script{{with nonce}} nonce="{{.}}"{{end}}>
+      document.getElementById('api-reference').dataset.configuration = JSON.stringify({{.Renderer}});
+    </script>
+    <script src="{{.ScriptURL}}"{{with .ScriptIntegrity}} integrity="{{.}}" crossorigin="anonymous"{{end}}></script>
+  </body>
+</html>
+`
+
+const elementsTmpl string = `
+<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>{{.Title}}</title>
+    <script src="{{.ScriptURL}}"{{with .ScriptIntegrity}} integrity="{{.}}" crossorigin="anonymous"{{end}}></script>
+    <link rel="stylesheet" href="{{.StyleURL}}"{{with .StyleIntegrity}} integrity="{{.}}" crossorigin="anonymous"{{end}}>
+    <style{{with nonce}} nonce="{{.}}"{{end}}>
+      body { margin: 0; height: 100vh; }
+    </style>
+  </head>
+  <body>
+    <elements-api
+      apiDescriptionUrl="{{.URL}}"
+      router="{{or .Renderer.Router "hash"}}"
+      layout="{{or .Renderer.Layout "sidebar"}}"
+      {{- if .Renderer.HideTryIt}} hideTryIt="true"{{end}}
+      {{- if .Renderer.HideSchemas}} hideSchemas="true"{{end}}
+      {{- if .Renderer.HideExport}} hideExport="true"{{end}}
+    ></elements-api>
+  </body>
+</html>
+`
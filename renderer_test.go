@@ -0,0 +1,111 @@
+package swagger
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_Renderers(t *testing.T) {
+	spec := BytesSpec([]byte(`{"swagger":"2.0","info":{"title":"API","version":"1.0"},"paths":{}}`))
+
+	tests := []struct {
+		name     string
+		renderer Renderer
+		expected []string
+	}{
+		{
+			name:     "Redoc",
+			renderer: RedocConfig{HideDownloadButton: true, Theme: map[string]interface{}{"sidebar": map[string]interface{}{"width": "300px"}}},
+			expected: []string{
+				`<script src="https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js">`,
+				`Redoc.init("/docs/doc.json", {"hideDownloadButton":true,"theme":{"sidebar":{"width":"300px"}}}`,
+			},
+		},
+		{
+			name:     "RapiDoc",
+			renderer: RapiDocConfig{Theme: "dark", DisableTry: true, ScriptURL: "/static/rapidoc-min.js"},
+			expected: []string{
+				`<script type="module" src="/static/rapidoc-min.js">`,
+				`spec-url="/docs/doc.json"`,
+				`heading-text="API Reference" theme="dark" allow-try="false"`,
+			},
+		},
+		{
+			name:     "Scalar",
+			renderer: ScalarConfig{Layout: "classic", DarkMode: true},
+			expected: []string{
+				`<script id="api-reference" data-url="/docs/doc.json">`,
+				`JSON.stringify({"layout":"classic","darkMode":true})`,
+				`<script src="https://cdn.jsdelivr.net/npm/@scalar/api-reference@1.25.0">`,
+			},
+		},
+		{
+			name:     "Stoplight Elements",
+			renderer: ElementsConfig{HideTryIt: true, ScriptIntegrity: "sha384-script", StyleIntegrity: "sha384-style"},
+			expected: []string{
+				`<script src="https://unpkg.com/@stoplight/elements@8.0.0/web-components.min.js" integrity="sha384-script" crossorigin="anonymous">`,
+				`<link rel="stylesheet" href="https://unpkg.com/@stoplight/elements@8.0.0/styles.min.css" integrity="sha384-style" crossorigin="anonymous">`,
+				`apiDescriptionUrl="/docs/doc.json"`,
+				`router="hash"`,
+				`layout="sidebar" hideTryIt="true"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/docs/*", New(Config{
+				Title:    "API Reference",
+				Spec:     spec,
+				Renderer: tt.renderer,
+			}))
+
+			req, err := http.NewRequest(http.MethodGet, "/docs/index.html", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != 200 {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 200)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(body), "<title>API Reference</title>") {
+				t.Fatal("index.html does not contain the title")
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(string(body), expected) {
+					t.Fatalf("index.html does not contain %s:\n%s", expected, body)
+				}
+			}
+
+			// The document is still served by the middleware
+			req, err = http.NewRequest(http.MethodGet, "/docs/doc.json", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err = app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != 200 {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 200)
+			}
+		})
+	}
+}
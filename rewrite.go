@@ -0,0 +1,101 @@
+package swagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// transformer edits a JSON document for the current request
+type transformer func(c *fiber.Ctx, doc []byte) ([]byte, error)
+
+// newTransformer returns the per-request document transformation configured in cfg, or nil if there is none
+func newTransformer(cfg Config) transformer {
+	if !cfg.RewriteServers && cfg.SpecTransformer == nil {
+		return nil
+	}
+
+	return func(c *fiber.Ctx, doc []byte) ([]byte, error) {
+		var m map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(doc))
+		dec.UseNumber()
+		if err := dec.Decode(&m); err != nil {
+			return nil, fmt.Errorf("invalid json document: %w", err)
+		}
+
+		if cfg.RewriteServers {
+			rewriteServers(c, m)
+		}
+
+		if cfg.SpecTransformer != nil {
+			if err := cfg.SpecTransformer(c, m); err != nil {
+				return nil, err
+			}
+		}
+
+		return marshalJSON(m)
+	}
+}
+
+// rewriteServers points the document at the host the request was received on.
+// Swagger 2.0 documents get new host, basePath and schemes, OpenAPI 3 documents new servers.
+func rewriteServers(c *fiber.Ctx, doc map[string]interface{}) {
+	scheme := c.Protocol()
+	host := c.Hostname()
+	prefix := getForwardedPrefix(c)
+
+	if _, ok := doc["openapi"]; ok {
+		servers, _ := doc["servers"].([]interface{})
+		if len(servers) == 0 {
+			servers = []interface{}{map[string]interface{}{"url": "/"}}
+		}
+
+		for _, s := range servers {
+			server, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			rawURL, _ := server["url"].(string)
+			// Templated server urls are resolved by the client, leave them alone
+			if strings.Contains(rawURL, "{") {
+				continue
+			}
+			u, err := url.Parse(rawURL)
+			if err != nil {
+				continue
+			}
+			server["url"] = scheme + "://" + host + joinPath(prefix, u.Path)
+		}
+		doc["servers"] = servers
+		return
+	}
+
+	basePath, _ := doc["basePath"].(string)
+	doc["host"] = host
+	doc["basePath"] = joinPath(prefix, basePath)
+	doc["schemes"] = []interface{}{scheme}
+}
+
+// joinPath joins prefix and p, keeping a leading slash and dropping a trailing one
+func joinPath(prefix, p string) string {
+	joined := strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(p, "/")
+	if len(joined) > 1 {
+		joined = strings.TrimSuffix(joined, "/")
+	}
+	return joined
+}
+
+// marshalJSON encodes v without escaping HTML characters, which are common in descriptions
+func marshalJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
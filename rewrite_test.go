@@ -0,0 +1,131 @@
+package swagger
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_Rewrite_Servers(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		headers map[string]string
+		body    string
+	}{
+		{
+			name: "Swagger 2.0 document",
+			doc:  `{"swagger":"2.0","host":"petstore.swagger.io","basePath":"/v2","schemes":["http"]}`,
+			headers: map[string]string{
+				"X-Forwarded-Host":   "api.example.com",
+				"X-Forwarded-Proto":  "https",
+				"X-Forwarded-Prefix": "/custom/path/",
+			},
+			body: `{"basePath":"/custom/path/v2","host":"api.example.com","schemes":["https"],"swagger":"2.0"}`,
+		},
+		{
+			name: "Swagger 2.0 document without basePath",
+			doc:  `{"swagger":"2.0"}`,
+			body: `{"basePath":"/","host":"example.com","schemes":["http"],"swagger":"2.0"}`,
+		},
+		{
+			name: "OpenAPI 3 document",
+			doc:  `{"openapi":"3.0.3","servers":[{"url":"https://petstore.swagger.io/v2"},{"url":"{scheme}://sandbox/v2"}]}`,
+			headers: map[string]string{
+				"X-Forwarded-Host":  "preview.example.com",
+				"X-Forwarded-Proto": "https",
+			},
+			body: `{"openapi":"3.0.3","servers":[{"url":"https://preview.example.com/v2"},{"url":"{scheme}://sandbox/v2"}]}`,
+		},
+		{
+			name: "OpenAPI 3 document without servers",
+			doc:  `{"openapi":"3.1.0"}`,
+			body: `{"openapi":"3.1.0","servers":[{"url":"http://example.com/"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/swag/*", New(Config{
+				Spec:           BytesSpec([]byte(tt.doc)),
+				RewriteServers: true,
+			}))
+
+			req, err := http.NewRequest(http.MethodGet, "http://example.com/swag/doc.json", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(body) != tt.body {
+				t.Fatalf("Body: got %s - expected %s", body, tt.body)
+			}
+		})
+	}
+}
+
+func Test_Spec_Transformer(t *testing.T) {
+	app := fiber.New()
+	app.Get("/swag/*", New(Config{
+		Spec: BytesSpec([]byte("swagger: \"2.0\"\ninfo:\n  title: API\n")),
+		SpecTransformer: func(c *fiber.Ctx, doc map[string]interface{}) error {
+			if c.Query("fail") != "" {
+				return errors.New("transformer failed")
+			}
+			doc["info"].(map[string]interface{})["title"] = "API <" + c.Hostname() + ">"
+			return nil
+		},
+	}))
+
+	t.Run("Should apply the transformer to YAML sources", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "http://example.com/swag/doc.yaml", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := "info:\n  title: API <example.com>\nswagger: \"2.0\"\n"
+		if string(body) != expected {
+			t.Fatalf("Body: got %s - expected %s", body, expected)
+		}
+	})
+
+	t.Run("Should return transformer errors", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "http://example.com/swag/doc.json?fail=1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != 500 {
+			t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 500)
+		}
+	})
+}
@@ -0,0 +1,463 @@
+package swagger
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// direction tells whether a value is sent by the client or by the server, which changes the meaning
+// of readOnly and writeOnly properties
+type direction int
+
+const (
+	toServer direction = iota
+	toClient
+)
+
+// Upper bound of nested schemas, guards against recursive definitions
+const maxSchemaDepth = 64
+
+// Upper bound of nested schemas when validating whole documents against their JSON Schema, which
+// nests a few schemas per level of the document
+const maxDocumentDepth = 1024
+
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// schemaError is a mismatch between a value and its schema
+type schemaError struct {
+	pointer string
+	message string
+}
+
+// schemaValidator validates decoded JSON values, numbers being json.Number, against the schemas of a document
+type schemaValidator struct {
+	model     *apiModel
+	direction direction
+	// Upper bound of nested schemas, maxSchemaDepth when zero
+	maxDepth int
+	errors   []schemaError
+}
+
+func (sv *schemaValidator) report(pointer, format string, args ...interface{}) {
+	sv.errors = append(sv.errors, schemaError{pointer: pointer, message: fmt.Sprintf(format, args...)})
+}
+
+// validate checks value against schema, the mismatches are collected in sv.errors
+func (sv *schemaValidator) validate(schema interface{}, value interface{}, pointer string, depth int) {
+	s, ok := schema.(map[string]interface{})
+	if !ok || len(s) == 0 {
+		// Missing schemas and true accept anything
+		if b, ok := schema.(bool); ok && !b {
+			sv.report(pointer, "no value is allowed")
+		}
+		return
+	}
+	maxDepth := sv.maxDepth
+	if maxDepth == 0 {
+		maxDepth = maxSchemaDepth
+	}
+	if depth > maxDepth {
+		sv.report(pointer, "schema is nested too deeply")
+		return
+	}
+
+	if ref, ok := s["$ref"].(string); ok {
+		target := sv.model.resolve(ref)
+		if target == nil {
+			sv.report(pointer, "unresolved reference %q", ref)
+			return
+		}
+		sv.validate(target, value, pointer, depth+1)
+	}
+
+	if value == nil && nullable(s) {
+		return
+	}
+
+	if !sv.validType(s, value, pointer) {
+		return
+	}
+
+	if enum, ok := s["enum"].([]interface{}); ok && !containsValue(enum, value) {
+		sv.report(pointer, "value is not one of %s", compactJSON(enum))
+	}
+	if c, ok := s["const"]; ok && !equalValues(c, value) {
+		sv.report(pointer, "value must be %s", compactJSON(c))
+	}
+
+	switch v := value.(type) {
+	case string:
+		sv.validateString(s, v, pointer)
+	case json.Number:
+		sv.validateNumber(s, v, pointer)
+	case []interface{}:
+		sv.validateArray(s, v, pointer, depth)
+	case map[string]interface{}:
+		sv.validateObject(s, v, pointer, depth)
+	}
+
+	sv.validateComposition(s, value, pointer, depth)
+}
+
+// validType checks the type keyword, it reports whether the other keywords apply
+func (sv *schemaValidator) validType(s map[string]interface{}, value interface{}, pointer string) bool {
+	var types []string
+	switch t := s["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		types = stringSlice(t)
+	default:
+		return true
+	}
+	actual := jsonType(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+		if t == "integer" && actual == "number" {
+			if f, err := value.(json.Number).Float64(); err == nil && f == math.Trunc(f) {
+				return true
+			}
+		}
+	}
+	sv.report(pointer, "expected %s, got %s", strings.Join(types, " or "), actual)
+	return false
+}
+
+func (sv *schemaValidator) validateString(s map[string]interface{}, v, pointer string) {
+	length := utf8.RuneCountInString(v)
+	if n, ok := schemaInt(s, "minLength"); ok && length < n {
+		sv.report(pointer, "string is shorter than %d characters", n)
+	}
+	if n, ok := schemaInt(s, "maxLength"); ok && length > n {
+		sv.report(pointer, "string is longer than %d characters", n)
+	}
+	if pattern, ok := s["pattern"].(string); ok {
+		if re, err := compilePattern(pattern); err == nil && !re.MatchString(v) {
+			sv.report(pointer, "string does not match pattern %q", pattern)
+		}
+	}
+
+	format, _ := s["format"].(string)
+	if format != "" && !validFormat(format, v) {
+		sv.report(pointer, "string is not a valid %s", format)
+	}
+}
+
+func (sv *schemaValidator) validateNumber(s map[string]interface{}, v json.Number, pointer string) {
+	f, err := v.Float64()
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) || !jsonNumberRe.MatchString(v.String()) {
+		sv.report(pointer, "invalid number %s", v)
+		return
+	}
+
+	if minimum, ok := schemaFloat(s, "minimum"); ok {
+		if exclusive, _ := s["exclusiveMinimum"].(bool); exclusive && f <= minimum {
+			sv.report(pointer, "value must be greater than %v", minimum)
+		} else if f < minimum {
+			sv.report(pointer, "value must be at least %v", minimum)
+		}
+	}
+	if maximum, ok := schemaFloat(s, "maximum"); ok {
+		if exclusive, _ := s["exclusiveMaximum"].(bool); exclusive && f >= maximum {
+			sv.report(pointer, "value must be less than %v", maximum)
+		} else if f > maximum {
+			sv.report(pointer, "value must be at most %v", maximum)
+		}
+	}
+	// OpenAPI 3.1 exclusive bounds are numbers
+	if bound, ok := schemaFloat(s, "exclusiveMinimum"); ok && f <= bound {
+		sv.report(pointer, "value must be greater than %v", bound)
+	}
+	if bound, ok := schemaFloat(s, "exclusiveMaximum"); ok && f >= bound {
+		sv.report(pointer, "value must be less than %v", bound)
+	}
+	if multiple, ok := schemaFloat(s, "multipleOf"); ok && multiple > 0 {
+		if q := f / multiple; math.Abs(q-math.Round(q)) > 1e-9 {
+			sv.report(pointer, "value must be a multiple of %v", multiple)
+		}
+	}
+
+	switch s["format"] {
+	case "int32":
+		if f < math.MinInt32 || f > math.MaxInt32 {
+			sv.report(pointer, "value is out of the int32 range")
+		}
+	case "int64":
+		if !strings.ContainsAny(v.String(), ".eE") {
+			if _, err := strconv.ParseInt(v.String(), 10, 64); err != nil {
+				sv.report(pointer, "value is out of the int64 range")
+			}
+		}
+	}
+}
+
+func (sv *schemaValidator) validateArray(s map[string]interface{}, v []interface{}, pointer string, depth int) {
+	if n, ok := schemaInt(s, "minItems"); ok && len(v) < n {
+		sv.report(pointer, "array has fewer than %d items", n)
+	}
+	if n, ok := schemaInt(s, "maxItems"); ok && len(v) > n {
+		sv.report(pointer, "array has more than %d items", n)
+	}
+	if unique, _ := s["uniqueItems"].(bool); unique {
+		for i := range v {
+			for j := i + 1; j < len(v); j++ {
+				if equalValues(v[i], v[j]) {
+					sv.report(pointer, "array items %d and %d are equal", i, j)
+				}
+			}
+		}
+	}
+
+	prefix, _ := s["prefixItems"].([]interface{})
+	for i, item := range v {
+		if i < len(prefix) {
+			sv.validate(prefix[i], item, pointer+"/"+strconv.Itoa(i), depth+1)
+			continue
+		}
+		if items, ok := s["items"]; ok {
+			sv.validate(items, item, pointer+"/"+strconv.Itoa(i), depth+1)
+		}
+	}
+}
+
+func (sv *schemaValidator) validateObject(s map[string]interface{}, v map[string]interface{}, pointer string, depth int) {
+	properties, _ := s["properties"].(map[string]interface{})
+
+	for _, name := range stringSlice(s["required"]) {
+		if _, ok := v[name]; ok {
+			continue
+		}
+		// Read-only properties are not sent by clients, write-only ones are not returned
+		if prop := sv.model.deref(properties[name]); prop != nil {
+			if readOnly, _ := prop["readOnly"].(bool); readOnly && sv.direction == toServer {
+				continue
+			}
+			if writeOnly, _ := prop["writeOnly"].(bool); writeOnly && sv.direction == toClient {
+				continue
+			}
+		}
+		sv.report(pointer+"/"+escapePointer(name), "property is required")
+	}
+
+	if n, ok := schemaInt(s, "minProperties"); ok && len(v) < n {
+		sv.report(pointer, "object has fewer than %d properties", n)
+	}
+	if n, ok := schemaInt(s, "maxProperties"); ok && len(v) > n {
+		sv.report(pointer, "object has more than %d properties", n)
+	}
+
+	// Sorted so that errors come in a stable order
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	patternProperties, _ := s["patternProperties"].(map[string]interface{})
+	patterns := make([]string, 0, len(patternProperties))
+	for pattern := range patternProperties {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	additional, hasAdditional := s["additionalProperties"]
+	for _, name := range names {
+		propPointer := pointer + "/" + escapePointer(name)
+		prop, matched := properties[name]
+		if matched {
+			sv.validate(prop, v[name], propPointer, depth+1)
+		}
+		// Properties matching a pattern are not additional either
+		for _, pattern := range patterns {
+			if re, err := compilePattern(pattern); err == nil && re.MatchString(name) {
+				matched = true
+				sv.validate(patternProperties[pattern], v[name], propPointer, depth+1)
+			}
+		}
+		if matched || !hasAdditional {
+			continue
+		}
+		if allowed, ok := additional.(bool); ok {
+			if !allowed {
+				sv.report(propPointer, "property is not allowed")
+			}
+			continue
+		}
+		sv.validate(additional, v[name], propPointer, depth+1)
+	}
+}
+
+func (sv *schemaValidator) validateComposition(s map[string]interface{}, value interface{}, pointer string, depth int) {
+	if allOf, ok := s["allOf"].([]interface{}); ok {
+		for _, sub := range allOf {
+			sv.validate(sub, value, pointer, depth+1)
+		}
+	}
+
+	if anyOf, ok := s["anyOf"].([]interface{}); ok && sv.matches(anyOf, value, pointer, depth) == 0 {
+		sv.report(pointer, "value does not match any of the schemas")
+	}
+
+	if oneOf, ok := s["oneOf"].([]interface{}); ok {
+		switch n := sv.matches(oneOf, value, pointer, depth); {
+		case n == 0:
+			sv.report(pointer, "value does not match any of the schemas")
+		case n > 1:
+			sv.report(pointer, "value matches %d schemas instead of one", n)
+		}
+	}
+
+	if not, ok := s["not"]; ok {
+		if sv.matches([]interface{}{not}, value, pointer, depth) == 1 {
+			sv.report(pointer, "value must not match the schema")
+		}
+	}
+}
+
+// matches counts the schemas value is valid against
+func (sv *schemaValidator) matches(schemas []interface{}, value interface{}, pointer string, depth int) int {
+	n := 0
+	for _, sub := range schemas {
+		probe := &schemaValidator{model: sv.model, direction: sv.direction, maxDepth: sv.maxDepth}
+		probe.validate(sub, value, pointer, depth+1)
+		if len(probe.errors) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// nullable reports whether the schema accepts null, with the OpenAPI 3.0 keyword or a 3.1 type list
+func nullable(s map[string]interface{}) bool {
+	if n, _ := s["nullable"].(bool); n {
+		return true
+	}
+	if types, ok := s["type"].([]interface{}); ok {
+		return containsString(stringSlice(types), "null")
+	}
+	return false
+}
+
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func validFormat(format, v string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, v)
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", v)
+		return err == nil
+	case "uuid":
+		return uuidRe.MatchString(v)
+	case "email":
+		addr, err := mail.ParseAddress(v)
+		return err == nil && addr.Address == v
+	case "ipv4":
+		ip := net.ParseIP(v)
+		return ip != nil && ip.To4() != nil && !strings.Contains(v, ":")
+	case "ipv6":
+		ip := net.ParseIP(v)
+		return ip != nil && strings.Contains(v, ":")
+	case "uri":
+		u, err := url.Parse(v)
+		return err == nil && u.IsAbs()
+	case "byte":
+		_, err := base64.StdEncoding.DecodeString(v)
+		return err == nil
+	}
+	// Unknown formats are annotations
+	return true
+}
+
+func schemaFloat(s map[string]interface{}, key string) (float64, bool) {
+	n, ok := s[key].(json.Number)
+	if !ok {
+		return 0, false
+	}
+	f, err := n.Float64()
+	return f, err == nil
+}
+
+func schemaInt(s map[string]interface{}, key string) (int, bool) {
+	f, ok := schemaFloat(s, key)
+	return int(f), ok
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if equalValues(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalValues compares decoded JSON values, numbers by value
+func equalValues(a, b interface{}) bool {
+	an, aok := a.(json.Number)
+	bn, bok := b.(json.Number)
+	if aok && bok {
+		af, aerr := an.Float64()
+		bf, berr := bn.Float64()
+		return aerr == nil && berr == nil && af == bf
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func compactJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// Patterns of the documents, compiled once
+var patterns sync.Map
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
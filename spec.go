@@ -0,0 +1,210 @@
+package swagger
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/swaggo/swag"
+)
+
+// Spec is a source of an API definition served by the middleware.
+type Spec interface {
+	// Read returns the document along with its media type, either JSON or YAML.
+	// An empty media type lets the middleware detect the format from the content.
+	Read(ctx context.Context) ([]byte, string, error)
+}
+
+// SpecFunc is an adapter to use an ordinary function as a Spec.
+type SpecFunc func(ctx context.Context) ([]byte, string, error)
+
+// Read calls f(ctx).
+func (f SpecFunc) Read(ctx context.Context) ([]byte, string, error) {
+	return f(ctx)
+}
+
+// SwagSpec returns a Spec reading the document of a registered swag instance.
+// An empty name refers to the default instance.
+func SwagSpec(instanceName string) Spec {
+	return SpecFunc(func(_ context.Context) ([]byte, string, error) {
+		doc, err := swag.ReadDoc(instanceName)
+		if err != nil {
+			return nil, "", err
+		}
+		return []byte(doc), fiber.MIMEApplicationJSON, nil
+	})
+}
+
+// FileSpec returns a Spec reading the document from a file on disk on every call.
+// The format is derived from the file extension.
+func FileSpec(path string) Spec {
+	return SpecFunc(func(_ context.Context) ([]byte, string, error) {
+		doc, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", err
+		}
+		return doc, mimeByExtension(path), nil
+	})
+}
+
+// FSSpec returns a Spec reading the document named name from fsys, e.g. an embed.FS.
+// The format is derived from the file extension.
+func FSSpec(fsys fs.FS, name string) Spec {
+	return SpecFunc(func(_ context.Context) ([]byte, string, error) {
+		doc, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, "", err
+		}
+		return doc, mimeByExtension(name), nil
+	})
+}
+
+// BytesSpec returns a Spec serving the given document. The format is detected from the content.
+func BytesSpec(doc []byte) Spec {
+	return SpecFunc(func(_ context.Context) ([]byte, string, error) {
+		return doc, "", nil
+	})
+}
+
+// document is an API definition served by the middleware
+type document struct {
+	spec      Spec
+	transform transformer
+	filters   *audienceFilters
+	delivery  delivery
+	// OpenAPI version served under openapi.json, empty when the document is not converted
+	outputVersion string
+	cache         docCache
+}
+
+// send writes the document in the format matching the requested file name
+func (d *document) send(c *fiber.Ctx, name string) error {
+	// The OpenAPI 3 names serve the converted document when an output version is set
+	upgrade := d.outputVersion != "" && (name == defaultOpenAPIURL || name == defaultOpenAPIYAMLURL)
+	if name == defaultOpenAPIURL && !upgrade {
+		return fiber.ErrNotFound
+	}
+
+	var mime string
+	switch name {
+	case defaultDocURL, defaultOpenAPIURL:
+		mime = fiber.MIMEApplicationJSON
+	case defaultDocName:
+		c.Vary(fiber.HeaderAccept)
+		mime = c.Accepts(append([]string{fiber.MIMEApplicationJSON}, yamlMIMEs...)...)
+		if mime == "" {
+			return fiber.ErrNotAcceptable
+		}
+		if mime != fiber.MIMEApplicationJSON {
+			mime = mimeYAML
+		}
+	default:
+		mime = mimeYAML
+	}
+
+	doc, isYAML, err := readSpec(c.UserContext(), d.spec)
+	if err != nil {
+		return err
+	}
+
+	r, err := d.render(c, doc, isYAML, mime, upgrade)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, mime)
+	return d.delivery.send(c, r)
+}
+
+// render returns the rendition of doc in the given media type, converted to OpenAPI 3 if upgrade is set
+func (d *document) render(c *fiber.Ctx, doc []byte, isYAML bool, mime string, upgrade bool) (*rendition, error) {
+	filter, audience := d.filters.lookup(c)
+
+	// Steps building the cached rendition, conversion, filtering and transformations work on JSON
+	var steps []func([]byte) ([]byte, error)
+	if isYAML && (upgrade || filter != nil || d.transform != nil || mime == fiber.MIMEApplicationJSON) {
+		steps = append(steps, yamlToJSON)
+		isYAML = false
+	}
+	var variant string
+	if upgrade {
+		steps = append(steps, func(doc []byte) ([]byte, error) {
+			return convertToOpenAPI3(doc, d.outputVersion)
+		})
+		variant = ";version=" + d.outputVersion
+	}
+	if filter != nil {
+		steps = append(steps, filter.apply)
+		variant += ";audience=" + audience
+	}
+
+	if d.transform == nil {
+		if !isYAML && mime == mimeYAML {
+			steps = append(steps, jsonToYAML)
+		}
+		return d.cache.get(doc, mime+variant, chain(steps))
+	}
+
+	source, err := d.cache.get(doc, fiber.MIMEApplicationJSON+variant, chain(steps))
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := d.transform(c, source.body)
+	if err != nil {
+		return nil, err
+	}
+
+	// The result is specific to this request, so it is not worth caching
+	if mime == mimeYAML {
+		if out, err = jsonToYAML(out); err != nil {
+			return nil, err
+		}
+	}
+	return newTransientRendition(out, source.modTime), nil
+}
+
+// chain composes conversion steps, it returns nil when there is nothing to do
+func chain(steps []func([]byte) ([]byte, error)) func([]byte) ([]byte, error) {
+	if len(steps) == 0 {
+		return nil
+	}
+	return func(doc []byte) ([]byte, error) {
+		var err error
+		for _, step := range steps {
+			if doc, err = step(doc); err != nil {
+				return nil, err
+			}
+		}
+		return doc, nil
+	}
+}
+
+// readSpec reads the document from spec and reports whether it is a YAML document
+func readSpec(ctx context.Context, spec Spec) ([]byte, bool, error) {
+	doc, mime, err := spec.Read(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if mime == "" {
+		// JSON documents always start with an object
+		return doc, !bytes.HasPrefix(bytes.TrimSpace(doc), []byte("{")), nil
+	}
+
+	return doc, strings.Contains(strings.ToLower(mime), "yaml"), nil
+}
+
+func mimeByExtension(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		return mimeYAML
+	case ".json":
+		return fiber.MIMEApplicationJSON
+	}
+	return ""
+}
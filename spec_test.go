@@ -0,0 +1,132 @@
+package swagger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const yamlDoc = `swagger: "2.0"
+info:
+  title: File API
+  version: "1.0"
+paths: {}
+`
+
+func Test_Spec_Sources(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "api.yaml")
+	if err := os.WriteFile(file, []byte(yamlDoc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		spec        Spec
+		url         string
+		statusCode  int
+		contentType string
+		body        string
+	}{
+		{
+			name:        "File source converted to JSON",
+			spec:        FileSpec(file),
+			url:         "/swag/doc.json",
+			statusCode:  200,
+			contentType: "application/json",
+			body:        `{"swagger":"2.0","info":{"title":"File API","version":"1.0"},"paths":{}}`,
+		},
+		{
+			name:        "File source served as YAML",
+			spec:        FileSpec(file),
+			url:         "/swag/doc.yaml",
+			statusCode:  200,
+			contentType: "application/yaml",
+			body:        yamlDoc,
+		},
+		{
+			name:        "FS source",
+			spec:        FSSpec(fstest.MapFS{"docs/api.json": {Data: []byte(`{"openapi":"3.0.3"}`)}}, "docs/api.json"),
+			url:         "/swag/doc.json",
+			statusCode:  200,
+			contentType: "application/json",
+			body:        `{"openapi":"3.0.3"}`,
+		},
+		{
+			name:        "Bytes source with detected format",
+			spec:        BytesSpec([]byte(yamlDoc)),
+			url:         "/swag/doc.json",
+			statusCode:  200,
+			contentType: "application/json",
+			body:        `{"swagger":"2.0","info":{"title":"File API","version":"1.0"},"paths":{}}`,
+		},
+		{
+			name: "Func source",
+			spec: SpecFunc(func(_ context.Context) ([]byte, string, error) {
+				return []byte(`{"openapi":"3.1.0"}`), "application/json; charset=utf-8", nil
+			}),
+			url:         "/swag/doc.json",
+			statusCode:  200,
+			contentType: "application/json",
+			body:        `{"openapi":"3.1.0"}`,
+		},
+		{
+			name: "Func source with error",
+			spec: SpecFunc(func(_ context.Context) ([]byte, string, error) {
+				return nil, "", errors.New("unavailable")
+			}),
+			url:        "/swag/doc.json",
+			statusCode: 500,
+		},
+		{
+			name:       "Missing file",
+			spec:       FileSpec(filepath.Join(t.TempDir(), "missing.json")),
+			url:        "/swag/doc.json",
+			statusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/swag/*", New(Config{Spec: tt.spec}))
+
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tt.statusCode {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, tt.statusCode)
+			}
+
+			if tt.contentType != "" {
+				ct := resp.Header.Get("Content-Type")
+				if ct != tt.contentType {
+					t.Fatalf(`Content-Type: got %s - expected %s`, ct, tt.contentType)
+				}
+			}
+
+			if tt.body != "" {
+				body, err := io.ReadAll(resp.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(body) != tt.body {
+					t.Fatalf("Body: got %s - expected %s", body, tt.body)
+				}
+			}
+		})
+	}
+}
@@ -1,78 +1,390 @@
 package swagger
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"html/template"
 	"net/http"
 	"path"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/filesystem"
 	"github.com/gofiber/fiber/v2/utils"
-	swaggerFiles "github.com/swaggo/files/v2"
-	"github.com/swaggo/swag"
 )
 
 const (
-	defaultDocURL = "doc.json"
-	defaultIndex  = "index.html"
+	defaultDocURL     = "doc.json"
+	defaultDocYAMLURL = "doc.yaml"
+	defaultDocName    = "doc"
+	defaultIndex      = "index.html"
 )
 
 var HandlerDefault = New()
 
 // New returns custom handler
 func New(config ...Config) fiber.Handler {
-	cfg := configDefault(config...)
+	handler, err := newHandler(configDefault(config...), false)
+	if err != nil {
+		panic(err)
+	}
+	return handler
+}
+
+// NewE returns custom handler like New, but validates the documents first (see Validate)
+// and returns configuration and validation errors instead of panicking.
+func NewE(config ...Config) (fiber.Handler, error) {
+	return newHandler(configDefault(config...), true)
+}
+
+// newHandler returns the handler for cfg, strict makes invalid documents an error instead of warnings
+func newHandler(cfg Config, strict bool) (fiber.Handler, error) {
+	if cfg.Offline && cfg.Renderer != nil {
+		if script, style := cfg.Renderer.assets(); isExternalURL(script) || isExternalURL(style) {
+			return nil, errors.New("fiber: swagger middleware error -> offline mode requires same-origin asset urls for the renderer")
+		}
+	}
+
+	if cfg.Offline && cfg.Renderer == nil && isExternalURL(cfg.AssetsURL) {
+		return nil, errors.New("fiber: swagger middleware error -> offline mode requires a same-origin AssetsURL")
+	}
+
+	for _, u := range cfg.URLs {
+		// Such an entry would have no path of its own and show the main document
+		if u.Spec != nil && u.URL == "" && u.InstanceName == "" {
+			return nil, fmt.Errorf("fiber: swagger middleware error -> URLs entry %q has a Spec but no InstanceName", u.Name)
+		}
+	}
+
+	// The report lists every route of the application, which the filters are meant to hide
+	if cfg.RouteDrift && len(cfg.SpecFilters) > 0 {
+		return nil, errors.New("fiber: swagger middleware error -> RouteDrift cannot be combined with SpecFilters, serve drift.json from an internal handler")
+	}
 
-	index, err := template.New("swagger_index.html").Parse(indexTmpl)
+	assetFiles, err := assetFS(cfg.Assets)
 	if err != nil {
-		panic(fmt.Errorf("fiber: swagger middleware error -> %w", err))
+		return nil, err
+	}
+
+	if cfg.OutputVersion != "" {
+		version := openAPIVersion(cfg.OutputVersion)
+		if version == "" {
+			return nil, fmt.Errorf("fiber: swagger middleware error -> unsupported OutputVersion %q", cfg.OutputVersion)
+		}
+		cfg.OutputVersion = version
+	}
+
+	var px *proxy
+	if cfg.Proxy != nil {
+		var err error
+		if px, err = newProxy(cfg.Proxy); err != nil {
+			return nil, err
+		}
+	}
+
+	var reload *watcher
+	if cfg.HotReload != nil {
+		var err error
+		if reload, err = newWatcher(cfg.HotReload); err != nil {
+			return nil, err
+		}
+		cfg.Spec = reload
+		if cfg.ValidateSpec {
+			reload.onChange = func() { logFindings(validateDocuments(&cfg)) }
+		}
+	}
+
+	if strict {
+		if err := validateDocuments(&cfg); err != nil {
+			return nil, err
+		}
+	} else if cfg.ValidateSpec {
+		logFindings(validateDocuments(&cfg))
+	}
+
+	tmpl := indexTmpl
+	if cfg.Renderer != nil {
+		tmpl = cfg.Renderer.template()
+	}
+
+	// With a policy the page is rendered with a placeholder replaced by the nonce of each request
+	var placeholder string
+	if cfg.ContentSecurityPolicy != nil {
+		placeholder = newPlaceholder()
+	}
+
+	// Custom templates build on the Swagger UI page only
+	custom := cfg.IndexTemplate
+	if cfg.Renderer != nil {
+		custom = nil
+	}
+	// Assets served by the middleware are referenced under fingerprinted names
+	var prints *fingerprints
+	index, err := parseIndexTemplate(tmpl, custom, template.FuncMap{
+		"nonce": func() string { return placeholder },
+		"asset": func(name string) string {
+			if cfg.AssetsURL == "" {
+				name = prints.name(name)
+			}
+			return assetURL(cfg.AssetsURL, name)
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fiber: swagger middleware error -> %w", err)
+	}
+	prints = newFingerprints(assetFiles, templateAssets(index))
+
+	immutable := "public, " + immutableCacheControl
+	if cfg.Authorizer != nil {
+		immutable = "private, " + immutableCacheControl
 	}
 
 	var (
-		prefix string
-		once   sync.Once
-		fs     = filesystem.New(filesystem.Config{Root: http.FS(swaggerFiles.FS)})
+		mounts = mountCache{m: make(map[string]*mount)}
+		tf     = newTransformer(cfg)
+		af     = newAudienceFilters(&cfg)
+		dl     = delivery{cacheControl: cfg.CacheControl, compress: cfg.Compress}
+		docs   = map[string]*document{"": {spec: cfg.Spec, transform: tf, filters: af, delivery: dl, outputVersion: cfg.OutputVersion}}
+		assets *assetCache
+		fs     = filesystem.New(filesystem.Config{Root: http.FS(assetFiles)})
+		fonts  = filesystem.New(filesystem.Config{Root: http.FS(fontFiles)})
 	)
 
+	if cfg.Compress {
+		assets = newAssetCache(assetFiles)
+	}
+
+	// Every entry of URLs which is not hosted elsewhere is served under its own path
+	for _, u := range cfg.URLs {
+		if u.URL == "" && u.InstanceName != "" {
+			spec := u.Spec
+			if spec == nil {
+				spec = SwagSpec(u.InstanceName)
+			}
+			docs[u.InstanceName] = &document{spec: spec, transform: tf, filters: af, delivery: dl, outputVersion: cfg.OutputVersion}
+		}
+	}
+
 	return func(c *fiber.Ctx) error {
-		// Set prefix
-		once.Do(
-			func() {
-				prefix = strings.ReplaceAll(c.Route().Path, "*", "")
-
-				forwardedPrefix := getForwardedPrefix(c)
-				if forwardedPrefix != "" {
-					prefix = forwardedPrefix + prefix
-				}
+		// The document on disk is watched while the application serving it runs
+		if reload != nil {
+			reload.start(c.App())
+		}
 
-				// Set doc url
-				if len(cfg.URL) == 0 {
-					cfg.URL = path.Join(prefix, defaultDocURL)
-				}
-			},
-		)
+		// Every file of the documentation is protected alike
+		if cfg.Authorizer != nil {
+			if err := authorize(c, &cfg); err != nil {
+				return err
+			}
+		}
+
+		// Resolve the prefix the handler is reached under
+		m := mounts.resolve(c, &cfg)
 
 		p := c.Path(utils.CopyString(c.Params("*")))
 
+		if px != nil && strings.HasPrefix(p, proxyDir) {
+			return px.forward(c, p[len(proxyDir):])
+		}
+
+		// Documents of the entries listed in URLs live under "<InstanceName>/"
+		if i := strings.LastIndexByte(p, '/'); i > 0 && isDocName(p[i+1:]) {
+			if doc, ok := docs[p[:i]]; ok {
+				return doc.send(c, p[i+1:])
+			}
+		}
+
+		if isDocName(p) {
+			return docs[""].send(c, p)
+		}
+
+		if p == defaultReloadURL && reload != nil {
+			return reload.serveEvents(c)
+		}
+
+		if p == defaultDriftURL && cfg.RouteDrift {
+			drift, err := diff(c.UserContext(), c.App(), cfg.Spec)
+			if err != nil {
+				return err
+			}
+			c.Set(fiber.HeaderCacheControl, "no-store")
+			return c.JSON(drift)
+		}
+
 		switch p {
 		case defaultIndex:
+			r, err := m.renderIndex(index)
+			if err != nil {
+				return err
+			}
 			c.Type("html")
-			return index.Execute(c, cfg)
-		case defaultDocURL:
-			var doc string
-			if doc, err = swag.ReadDoc(cfg.InstanceName); err != nil {
+			if csp := cfg.ContentSecurityPolicy; csp != nil {
+				nonce := newNonce()
+				c.Set(csp.header(), strings.ReplaceAll(m.policy, cspNonce, nonce))
+				body := bytes.ReplaceAll(r.body, []byte(placeholder), []byte(nonce))
+				// The page differs on every request and must not be reused
+				return delivery{cacheControl: "no-store", compress: cfg.Compress}.send(c, newTransientRendition(body, r.modTime))
+			}
+			return dl.send(c, r)
+		case defaultConfigURL:
+			r, err := m.renderConfig()
+			if err != nil {
 				return err
 			}
-			return c.Type("json").SendString(doc)
+			c.Type("json")
+			return dl.send(c, r)
 		case "", "/":
-			return c.Redirect(path.Join(prefix, defaultIndex), fiber.StatusMovedPermanently)
+			return c.Redirect(path.Join(m.prefix, defaultIndex), fiber.StatusMovedPermanently)
 		default:
+			if cfg.Offline && strings.HasPrefix(p, fontsDir) {
+				return fonts(c)
+			}
+			if name, r := prints.resolve(p); r != nil {
+				c.Type(path.Ext(name))
+				return delivery{cacheControl: immutable, compress: cfg.Compress}.send(c, r)
+			}
+			if assets != nil {
+				if r := assets.get(p); r != nil {
+					c.Type(path.Ext(p))
+					return dl.send(c, r)
+				}
+			}
 			return fs(c)
 		}
+	}, nil
+}
+
+// isDocName reports whether name is one of the file names the document is served under
+func isDocName(name string) bool {
+	switch name {
+	case defaultDocURL, defaultDocYAMLURL, "swagger.yaml", defaultOpenAPIURL, defaultOpenAPIYAMLURL, defaultDocName:
+		return true
+	}
+	return false
+}
+
+// Upper bound of cached mounts, the forwarded prefix is client controlled
+const maxMounts = 64
+
+// mount holds the values derived from the path prefix the handler is reached under
+type mount struct {
+	prefix string
+	// Copy of the configuration with the doc urls resolved against prefix
+	cfg Config
+	// Content-Security-Policy with a placeholder for the nonce
+	policy string
+
+	once  sync.Once
+	index *rendition
+	err   error
+
+	configOnce sync.Once
+	config     *rendition
+	configErr  error
+}
+
+// renderIndex executes the index template once for the mount
+func (m *mount) renderIndex(tmpl *template.Template) (*rendition, error) {
+	m.once.Do(func() {
+		var data interface{} = IndexData{Config: m.cfg, Prefix: m.prefix, DocURL: m.docURL()}
+		if m.cfg.Renderer != nil {
+			script, style := m.cfg.Renderer.assets()
+			scriptIntegrity, styleIntegrity := m.cfg.Renderer.integrity()
+			data = pageData{
+				Title: m.cfg.Title, URL: m.docURL(), ScriptURL: script, StyleURL: style,
+				ScriptIntegrity: scriptIntegrity, StyleIntegrity: styleIntegrity, Renderer: m.cfg.Renderer,
+			}
+		}
+
+		var buf bytes.Buffer
+		if m.err = tmpl.Execute(&buf, data); m.err == nil {
+			m.index = newRendition(buf.Bytes(), time.Now().UTC().Truncate(time.Second))
+		}
+	})
+	return m.index, m.err
+}
+
+// mountCache memoizes mounts by the route prefix and the forwarded prefix
+type mountCache struct {
+	mu sync.RWMutex
+	m  map[string]*mount
+}
+
+// resolve returns the mount of the current request
+func (mc *mountCache) resolve(c *fiber.Ctx, cfg *Config) *mount {
+	routePrefix := getRoutePrefix(c)
+	forwardedPrefix := getForwardedPrefix(c)
+	key := forwardedPrefix + "\x00" + routePrefix
+
+	mc.mu.RLock()
+	m, ok := mc.m[key]
+	mc.mu.RUnlock()
+	if ok {
+		return m
+	}
+
+	m = newMount(cfg, forwardedPrefix+routePrefix)
+
+	mc.mu.Lock()
+	if len(mc.m) < maxMounts {
+		mc.m[key] = m
+	}
+	mc.mu.Unlock()
+
+	return m
+}
+
+// newMount resolves the doc urls of cfg against prefix without modifying cfg
+func newMount(cfg *Config, prefix string) *mount {
+	m := &mount{prefix: prefix, cfg: *cfg}
+
+	if len(cfg.URLs) > 0 {
+		urls := make([]SpecURL, len(cfg.URLs))
+		for i, u := range cfg.URLs {
+			if u.URL == "" {
+				u.URL = path.Join(prefix, u.InstanceName, defaultDocURL)
+			}
+			urls[i] = u
+		}
+		m.cfg.URLs = urls
+	} else if len(cfg.URL) == 0 {
+		m.cfg.URL = path.Join(prefix, defaultDocURL)
+	}
+
+	if cfg.ExternalConfig && cfg.ConfigURL == "" {
+		m.cfg.ConfigURL = path.Join(prefix, defaultConfigURL)
+	}
+
+	if cfg.ContentSecurityPolicy != nil {
+		m.policy = cfg.ContentSecurityPolicy.policy(&m.cfg)
+	}
+
+	return m
+}
+
+// docURL returns the url of the document shown first
+func (m *mount) docURL() string {
+	if len(m.cfg.URLs) == 0 {
+		return m.cfg.URL
+	}
+	for _, u := range m.cfg.URLs {
+		if u.Name == m.cfg.URLsPrimaryName {
+			return u.URL
+		}
+	}
+	return m.cfg.URLs[0].URL
+}
+
+// getRoutePrefix returns the request path up to the wildcard of the route
+func getRoutePrefix(c *fiber.Ctx) string {
+	p, wildcard := c.Path(), c.Params("*")
+	if strings.HasSuffix(p, wildcard) {
+		return utils.CopyString(p[:len(p)-len(wildcard)])
 	}
+	return strings.ReplaceAll(c.Route().Path, "*", "")
 }
 
 func getForwardedPrefix(c *fiber.Ctx) string {
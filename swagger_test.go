@@ -1,7 +1,9 @@
 package swagger
 
 import (
+	"io"
 	"net/http"
+	"strings"
 	"sync"
 	"testing"
 
@@ -37,6 +39,7 @@ func (s *mockedSwag) ReadDoc() string {
 
 var (
 	registrationOnce sync.Once
+	instancesOnce    sync.Once
 )
 
 func Test_Swagger(t *testing.T) {
@@ -51,6 +54,7 @@ func Test_Swagger(t *testing.T) {
 	tests := []struct {
 		name        string
 		url         string
+		accept      string
 		statusCode  int
 		contentType string
 		location    string
@@ -67,6 +71,37 @@ func Test_Swagger(t *testing.T) {
 			statusCode:  200,
 			contentType: "application/json",
 		},
+		{
+			name:        "Should be returns status 200 with 'application/yaml' content-type",
+			url:         "/swag/doc.yaml",
+			statusCode:  200,
+			contentType: "application/yaml",
+		},
+		{
+			name:        "Should be returns status 200 with 'application/yaml' content-type for alias",
+			url:         "/swag/openapi.yaml",
+			statusCode:  200,
+			contentType: "application/yaml",
+		},
+		{
+			name:        "Should be returns status 200 with 'application/json' content-type without Accept",
+			url:         "/swag/doc",
+			statusCode:  200,
+			contentType: "application/json",
+		},
+		{
+			name:        "Should be returns status 200 with 'application/yaml' content-type when preferred",
+			url:         "/swag/doc",
+			accept:      "application/json;q=0.5, application/x-yaml",
+			statusCode:  200,
+			contentType: "application/yaml",
+		},
+		{
+			name:       "Should return status 406 when no format is acceptable",
+			url:        "/swag/doc",
+			accept:     "text/plain",
+			statusCode: 406,
+		},
 		{
 			name:        "Should be returns status 200 with 'image/png' content-type",
 			url:         "/swag/favicon-16x16.png",
@@ -93,6 +128,10 @@ func Test_Swagger(t *testing.T) {
 				t.Fatal(err)
 			}
 
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
 			resp, err := app.Test(req)
 			if err != nil {
 				t.Fatal(err)
@@ -126,7 +165,6 @@ func Test_Swagger_Proxy_Redirect(t *testing.T) {
 		swag.Register(swag.Name, &mockedSwag{})
 	})
 
-	// Use new handler since the prefix is created only once per handler
 	app.Get("/swag/*", New())
 
 	statusCode := 301
@@ -157,3 +195,223 @@ func Test_Swagger_Proxy_Redirect(t *testing.T) {
 		}
 	})
 }
+
+func Test_Swagger_Multiple_Mounts(t *testing.T) {
+	app := fiber.New()
+
+	registrationOnce.Do(func() {
+		swag.Register(swag.Name, &mockedSwag{})
+	})
+
+	// The same handler is shared by every route
+	handler := New()
+	app.Get("/swag/*", handler)
+	app.Get("/docs/:version/*", handler)
+
+	tests := []struct {
+		name            string
+		url             string
+		forwardedPrefix string
+		location        string
+		docURL          string
+	}{
+		{
+			name:     "First mount",
+			url:      "/swag/",
+			location: "/swag/index.html",
+			docURL:   `"url":"/swag/doc.json"`,
+		},
+		{
+			name:            "First mount behind a proxy",
+			url:             "/swag/",
+			forwardedPrefix: "/custom/path/",
+			location:        "/custom/path/swag/index.html",
+			docURL:          `"url":"/custom/path/swag/doc.json"`,
+		},
+		{
+			name:     "Second mount with a parameter",
+			url:      "/docs/v2/",
+			location: "/docs/v2/index.html",
+			docURL:   `"url":"/docs/v2/doc.json"`,
+		},
+		{
+			name:     "First mount again",
+			url:      "/swag/",
+			location: "/swag/index.html",
+			docURL:   `"url":"/swag/doc.json"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.forwardedPrefix != "" {
+				req.Header.Set("X-Forwarded-Prefix", tt.forwardedPrefix)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if location := resp.Header.Get("Location"); location != tt.location {
+				t.Fatalf(`Location: got %s - expected %s`, location, tt.location)
+			}
+
+			req, err = http.NewRequest(http.MethodGet, tt.url+"index.html", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.forwardedPrefix != "" {
+				req.Header.Set("X-Forwarded-Prefix", tt.forwardedPrefix)
+			}
+
+			resp, err = app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(body), tt.docURL) {
+				t.Fatalf("index.html does not contain %s", tt.docURL)
+			}
+		})
+	}
+}
+
+func Test_Swagger_URLs(t *testing.T) {
+	app := fiber.New()
+
+	instancesOnce.Do(func() {
+		swag.Register("v1", &mockedSwag{})
+		swag.Register("admin", &mockedSwag{})
+	})
+
+	app.Get("/swag/*", New(Config{
+		URLs: []SpecURL{
+			{Name: "Public v1", InstanceName: "v1"},
+			{Name: "Admin", InstanceName: "admin"},
+			{Name: "Petstore", URL: "https://petstore.swagger.io/v2/swagger.json"},
+		},
+		URLsPrimaryName: "Admin",
+	}))
+
+	tests := []struct {
+		name        string
+		url         string
+		statusCode  int
+		contentType string
+	}{
+		{
+			name:        "Should serve the first instance",
+			url:         "/swag/v1/doc.json",
+			statusCode:  200,
+			contentType: "application/json",
+		},
+		{
+			name:        "Should serve the second instance as YAML",
+			url:         "/swag/admin/doc.yaml",
+			statusCode:  200,
+			contentType: "application/yaml",
+		},
+		{
+			name:       "Should return status 404 for unknown instances",
+			url:        "/swag/unknown/doc.json",
+			statusCode: 404,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tt.statusCode {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, tt.statusCode)
+			}
+
+			if tt.contentType != "" {
+				ct := resp.Header.Get("Content-Type")
+				if ct != tt.contentType {
+					t.Fatalf(`Content-Type: got %s - expected %s`, ct, tt.contentType)
+				}
+			}
+		})
+	}
+
+	t.Run("Should render the definition selector", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/swag/index.html", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, expected := range []string{
+			`"urls.primaryName":"Admin"`,
+			`"url":"/swag/v1/doc.json"`,
+			`"url":"/swag/admin/doc.json"`,
+			`"url":"https://petstore.swagger.io/v2/swagger.json"`,
+		} {
+			if !strings.Contains(string(body), expected) {
+				t.Fatalf("index.html does not contain %s", expected)
+			}
+		}
+	})
+
+	t.Run("Should reject a Spec without InstanceName", func(t *testing.T) {
+		_, err := NewE(Config{
+			Spec: BytesSpec([]byte(`{"swagger":"2.0","info":{"title":"API","version":"1"},"paths":{}}`)),
+			URLs: []SpecURL{{Name: "Other", Spec: BytesSpec([]byte(`{"swagger":"2.0"}`))}},
+		})
+		if err == nil || !strings.Contains(err.Error(), `URLs entry "Other" has a Spec but no InstanceName`) {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+}
+
+func Test_JSONToYAML(t *testing.T) {
+	out, err := jsonToYAML([]byte(`{"swagger":"2.0","info":{"title":"API","version":"1.0"},"basePath":"/v2","tags":[{"name":"b"},{"name":"a"}],"x-max":1.5}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `swagger: "2.0"
+info:
+  title: API
+  version: "1.0"
+basePath: /v2
+tags:
+- name: b
+- name: a
+x-max: 1.5
+`
+	if string(out) != expected {
+		t.Fatalf("YAML: got\n%s\nexpected\n%s", out, expected)
+	}
+
+	if _, err := jsonToYAML([]byte(`{"swagger":`)); err == nil {
+		t.Fatal("expected error for malformed document")
+	}
+}
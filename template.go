@@ -0,0 +1,67 @@
+package swagger
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"text/template/parse"
+)
+
+// TemplateFS locates an index template in a file system, e.g. an embed.FS.
+type TemplateFS struct {
+	FS   fs.FS
+	Path string
+}
+
+// IndexData is the data index templates are executed with. The fields of Config are promoted,
+// so that {{.Title}} or {{.URL}} work as in the default template.
+type IndexData struct {
+	Config
+
+	// Path prefix the handler is reached under, e.g. "/swagger/"
+	Prefix string `json:"-"`
+
+	// URL of the document shown first
+	DocURL string `json:"-"`
+}
+
+// parseIndexTemplate parses the default index template, with the blocks and pages of custom layered on top.
+// custom is a template source, a *template.Template or a TemplateFS.
+func parseIndexTemplate(tmpl string, custom interface{}, funcs template.FuncMap) (*template.Template, error) {
+	index, err := template.New("swagger_index.html").Funcs(funcs).Parse(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := custom.(type) {
+	case nil:
+		return index, nil
+	case string:
+		// Definitions of only blocks override them, a template with a body replaces the whole page
+		return index.Parse(t)
+	case TemplateFS:
+		src, err := fs.ReadFile(t.FS, t.Path)
+		if err != nil {
+			return nil, err
+		}
+		return index.Parse(string(src))
+	case *template.Template:
+		for _, sub := range t.Templates() {
+			if sub.Tree == nil || sub.Tree.Root == nil {
+				continue
+			}
+			name := sub.Name()
+			if name == t.Name() {
+				if parse.IsEmptyTree(sub.Tree.Root) {
+					continue
+				}
+				name = index.Name()
+			}
+			if _, err := index.AddParseTree(name, sub.Tree.Copy()); err != nil {
+				return nil, err
+			}
+		}
+		return index, nil
+	}
+	return nil, fmt.Errorf("unsupported IndexTemplate type %T", custom)
+}
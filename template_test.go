@@ -0,0 +1,124 @@
+package swagger
+
+import (
+	"html/template"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_Index_Template(t *testing.T) {
+	custom := template.Must(template.New("custom").Parse(`{{define "after_ui"}}<footer>{{.Title}}</footer>{{end}}`))
+	files := fstest.MapFS{"templates/page.html": {Data: []byte(`<h1>{{.Title}}</h1><a href="{{.DocURL}}">doc</a>`)}}
+
+	tests := []struct {
+		name     string
+		template interface{}
+		contains []string
+		equals   string
+	}{
+		{
+			name: "Should override blocks of the default page",
+			template: `{{define "head"}}<meta name="brand" content="{{.Title}}">{{end}}` +
+				`{{define "before_ui"}}<header>{{.Prefix}} {{.DocURL}}</header>{{end}}`,
+			contains: []string{
+				`<meta name="brand" content="Docs">`,
+				`<header>/swag/ /swag/doc.json</header>`,
+				`<div id="swagger-ui"></div>`,
+				`"url":"/swag/doc.json"`,
+			},
+		},
+		{
+			name:     "Should override blocks with a parsed template",
+			template: custom,
+			contains: []string{`<footer>Docs</footer>`, `<div id="swagger-ui"></div>`},
+		},
+		{
+			name:     "Should replace the page with a template from a file system",
+			template: TemplateFS{FS: files, Path: "templates/page.html"},
+			equals:   `<h1>Docs</h1><a href="/swag/doc.json">doc</a>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/swag/*", New(Config{
+				Spec:          BytesSpec([]byte(`{"swagger":"2.0"}`)),
+				Title:         "Docs",
+				IndexTemplate: tt.template,
+			}))
+
+			req, err := http.NewRequest(http.MethodGet, "/swag/index.html", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != 200 {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 200)
+			}
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.equals != "" && string(body) != tt.equals {
+				t.Fatalf("Body: got %s - expected %s", body, tt.equals)
+			}
+			for _, expected := range tt.contains {
+				if !strings.Contains(string(body), expected) {
+					t.Fatalf("index.html does not contain %s", expected)
+				}
+			}
+		})
+	}
+}
+
+func Test_Index_Template_Nonce(t *testing.T) {
+	app := fiber.New()
+	app.Get("/swag/*", New(Config{
+		Spec:                  BytesSpec([]byte(`{"swagger":"2.0"}`)),
+		IndexTemplate:         `{{define "head"}}<script nonce="{{nonce}}">track()</script>{{end}}`,
+		ContentSecurityPolicy: &CSPConfig{},
+	}))
+
+	req, err := http.NewRequest(http.MethodGet, "/swag/index.html", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	match := nonceRe.FindStringSubmatch(resp.Header.Get("Content-Security-Policy"))
+	if match == nil {
+		t.Fatal("policy has no nonce")
+	}
+	if expected := `<script nonce="` + match[1] + `">track()</script>`; !strings.Contains(string(body), expected) {
+		t.Fatalf("index.html does not contain %s", expected)
+	}
+}
+
+func Test_Index_Template_Errors(t *testing.T) {
+	for _, custom := range []interface{}{
+		42,
+		`{{define "head"}}{{.Title}`,
+		TemplateFS{FS: fstest.MapFS{}, Path: "missing.html"},
+	} {
+		if _, err := parseIndexTemplate(indexTmpl, custom, template.FuncMap{"nonce": func() string { return "" }}); err == nil {
+			t.Fatalf("expected an error for %v", custom)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package swagger
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const defaultConfigURL = "swagger-config.json"
+
+// configDocument is the configuration of Swagger UI served under swagger-config.json
+type configDocument struct {
+	Config
+
+	// Swagger UI does not follow the configUrl of a fetched configuration
+	ConfigURL       string       `json:"configUrl,omitempty"`
+	Filter          interface{}  `json:"filter"`
+	SyntaxHighlight interface{}  `json:"syntaxHighlight"`
+	OAuth           *OAuthConfig `json:"oauth,omitempty"`
+}
+
+// renderConfig marshals the configuration document once for the mount
+func (m *mount) renderConfig() (*rendition, error) {
+	m.configOnce.Do(func() {
+		doc := configDocument{
+			Config:          m.cfg,
+			Filter:          m.cfg.Filter.Value(),
+			SyntaxHighlight: false,
+			OAuth:           m.cfg.OAuth,
+		}
+		if m.cfg.SyntaxHighlight != nil {
+			doc.SyntaxHighlight = m.cfg.SyntaxHighlight.Value()
+		}
+
+		var body []byte
+		if body, m.configErr = json.Marshal(doc); m.configErr == nil {
+			m.config = newRendition(body, time.Now().UTC().Truncate(time.Second))
+		}
+	})
+	return m.config, m.configErr
+}
@@ -0,0 +1,113 @@
+package swagger
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_Config_Document(t *testing.T) {
+	get := func(t *testing.T, app *fiber.App, url string) (*http.Response, string) {
+		t.Helper()
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp, string(body)
+	}
+
+	t.Run("Should serve the JSON options", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/swag/*", New(Config{
+			Spec:            BytesSpec([]byte(`{"swagger":"2.0"}`)),
+			URLs:            []SpecURL{{Name: "Public", InstanceName: "v1"}, {Name: "Petstore", URL: "https://petstore.swagger.io/v2/swagger.json"}},
+			URLsPrimaryName: "Petstore",
+			Filter:          FilterConfig{Expression: "pets"},
+			OAuth:           &OAuthConfig{ClientId: "docs", Scopes: []string{"read"}},
+			ExternalConfig:  true,
+		}))
+
+		resp, body := get(t, app, "/swag/swagger-config.json")
+		if resp.StatusCode != 200 {
+			t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, 200)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+			t.Fatalf(`Content-Type: got %s - expected %s`, ct, "application/json")
+		}
+
+		var doc map[string]interface{}
+		if err := json.Unmarshal([]byte(body), &doc); err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]interface{}{
+			"urls": []interface{}{
+				map[string]interface{}{"name": "Public", "url": "/swag/v1/doc.json"},
+				map[string]interface{}{"name": "Petstore", "url": "https://petstore.swagger.io/v2/swagger.json"},
+			},
+			"urls.primaryName":      "Petstore",
+			"layout":                "StandaloneLayout",
+			"deepLinking":           false,
+			"defaultModelRendering": "example",
+			"docExpansion":          "list",
+			"showMutatedRequest":    false,
+			"filter":                "pets",
+			"syntaxHighlight":       map[string]interface{}{"activate": true, "theme": "agate"},
+			"oauth":                 map[string]interface{}{"clientId": "docs", "scopes": []interface{}{"read"}},
+		}
+		if !reflect.DeepEqual(doc, expected) {
+			t.Fatalf("swagger-config.json: got %v - expected %v", doc, expected)
+		}
+	})
+
+	t.Run("Should reference the document from index.html", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/swag/*", New(Config{
+			Spec:               BytesSpec([]byte(`{"swagger":"2.0"}`)),
+			QueryConfigEnabled: true,
+			ExternalConfig:     true,
+		}))
+
+		_, body := get(t, app, "/swag/index.html")
+		expected := `config = {configUrl: "/swag/swagger-config.json", queryConfigEnabled:  true };`
+		if !strings.Contains(body, expected) {
+			t.Fatalf("index.html does not contain %s", expected)
+		}
+		if strings.Contains(body, `"url":`) {
+			t.Fatal("index.html inlines the configuration")
+		}
+	})
+
+	t.Run("Should keep a custom ConfigURL", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/swag/*", New(Config{
+			Spec:           BytesSpec([]byte(`{"swagger":"2.0"}`)),
+			ConfigURL:      "https://config.example.com/ui.json",
+			ExternalConfig: true,
+		}))
+
+		_, body := get(t, app, "/swag/index.html")
+		expected := `configUrl: "https://config.example.com/ui.json"`
+		if !strings.Contains(body, expected) {
+			t.Fatalf("index.html does not contain %s", expected)
+		}
+
+		_, body = get(t, app, "/swag/swagger-config.json")
+		if strings.Contains(body, "configUrl") {
+			t.Fatalf("swagger-config.json references itself: %s", body)
+		}
+	})
+}
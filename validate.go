@@ -0,0 +1,376 @@
+package swagger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// Finding is a problem found in a document by Validate.
+type Finding struct {
+	// Instance name of the document, see Config.InstanceName and SpecURL.InstanceName.
+	Instance string
+
+	// JSON pointer to the offending value, "" for the whole document.
+	Pointer string
+
+	// Description of the problem.
+	Message string
+}
+
+func (f Finding) String() string {
+	if f.Pointer == "" {
+		return fmt.Sprintf("%q: %s", f.Instance, f.Message)
+	}
+	return fmt.Sprintf("%q %s: %s", f.Instance, f.Pointer, f.Message)
+}
+
+// ValidationError lists the findings of Validate.
+type ValidationError struct {
+	Findings []Finding
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Findings))
+	for _, f := range e.Findings {
+		msgs = append(msgs, f.String())
+	}
+	return "fiber: swagger middleware error -> invalid document: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks the documents served by the middleware for config: the swag instances exist, the documents
+// parse and are valid against the JSON Schema of Swagger 2.0 or OpenAPI 3.0. On top of the schema, it checks
+// that local $refs resolve, path parameters are declared and operation ids are unique. OpenAPI 3.1 documents
+// only get these checks and those of the version, info, paths, parameters and responses.
+// Documents hosted elsewhere are not checked. It returns a *ValidationError listing the findings.
+func Validate(config ...Config) error {
+	cfg := configDefault(config...)
+	return validateDocuments(&cfg)
+}
+
+// validateDocuments validates the documents served for cfg
+func validateDocuments(cfg *Config) error {
+	var findings []Finding
+	findings = append(findings, validateSpec(cfg.InstanceName, cfg.Spec)...)
+	for _, u := range cfg.URLs {
+		if u.URL != "" || u.InstanceName == "" {
+			continue
+		}
+		spec := u.Spec
+		if spec == nil {
+			spec = SwagSpec(u.InstanceName)
+		}
+		findings = append(findings, validateSpec(u.InstanceName, spec)...)
+	}
+
+	if len(findings) > 0 {
+		return &ValidationError{Findings: findings}
+	}
+	return nil
+}
+
+// logFindings logs the findings of err as warnings
+func logFindings(err error) {
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		return
+	}
+	for _, f := range verr.Findings {
+		log.Warnw("swagger: invalid document", "instance", f.Instance, "pointer", f.Pointer, "message", f.Message)
+	}
+}
+
+func validateSpec(instance string, spec Spec) []Finding {
+	doc, isYAML, err := readSpec(context.Background(), spec)
+	if err != nil {
+		return []Finding{{Instance: instance, Message: "cannot read document: " + err.Error()}}
+	}
+	if isYAML {
+		if doc, err = yamlToJSON(doc); err != nil {
+			return []Finding{{Instance: instance, Message: err.Error()}}
+		}
+	}
+
+	var root interface{}
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	if err := dec.Decode(&root); err != nil {
+		return []Finding{{Instance: instance, Message: "invalid json: " + err.Error()}}
+	}
+
+	v := &docValidator{instance: instance}
+	v.document(root)
+	return v.findings
+}
+
+var (
+	openAPIVersionRe = regexp.MustCompile(`^3\.[01]\.\d+$`)
+	responseCodeRe   = regexp.MustCompile(`^([1-5](\d\d|XX)|default)$`)
+	pathParamRe      = regexp.MustCompile(`{([^}/]+)}`)
+)
+
+// docValidator checks the structure of a document
+type docValidator struct {
+	instance     string
+	root         map[string]interface{}
+	swagger2     bool
+	operationIDs map[string]string
+	findings     []Finding
+}
+
+func (v *docValidator) report(pointer, format string, args ...interface{}) {
+	v.findings = append(v.findings, Finding{Instance: v.instance, Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *docValidator) document(raw interface{}) {
+	root, ok := raw.(map[string]interface{})
+	if !ok {
+		v.report("", "document is not an object")
+		return
+	}
+	v.root = root
+	v.operationIDs = make(map[string]string)
+
+	swaggerVersion, hasSwagger := root["swagger"]
+	openapiVersion, hasOpenAPI := root["openapi"]
+	switch {
+	case hasSwagger:
+		if swaggerVersion != "2.0" {
+			v.report("/swagger", "unsupported version %v, expected \"2.0\"", swaggerVersion)
+		}
+		v.swagger2 = true
+	case hasOpenAPI:
+		if s, _ := openapiVersion.(string); !openAPIVersionRe.MatchString(s) {
+			v.report("/openapi", "unsupported version %v, expected 3.0.x or 3.1.x", openapiVersion)
+		}
+	default:
+		v.report("", "missing swagger or openapi version")
+		return
+	}
+
+	if info, ok := root["info"].(map[string]interface{}); !ok {
+		v.report("/info", "missing info object")
+	} else {
+		for _, field := range []string{"title", "version"} {
+			if _, ok := info[field].(string); !ok {
+				v.report("/info/"+field, "missing %s", field)
+			}
+		}
+	}
+
+	paths, ok := root["paths"].(map[string]interface{})
+	if !ok {
+		// OpenAPI 3.1 documents may only hold components or webhooks
+		_, hasComponents := root["components"]
+		_, hasWebhooks := root["webhooks"]
+		if !hasOpenAPI || !strings.HasPrefix(fmt.Sprint(openapiVersion), "3.1") || !(hasComponents || hasWebhooks) {
+			v.report("/paths", "missing paths object")
+		}
+	}
+	for p, item := range paths {
+		v.pathItem(p, item)
+	}
+
+	v.refs(root, "")
+	v.metaSchema(root)
+}
+
+// metaSchema validates the document against the JSON Schema of its version, leaving out the pointers
+// already reported by the structural checks
+func (v *docValidator) metaSchema(root map[string]interface{}) {
+	meta, err := metaSchema(root)
+	if err != nil {
+		v.report("", "cannot load the JSON Schema: %v", err)
+		return
+	}
+	if meta == nil {
+		return
+	}
+	if v.swagger2 {
+		root = withoutSwagPlaceholders(root)
+	}
+
+	reported := make(map[string]bool, len(v.findings))
+	for _, f := range v.findings {
+		reported[f.Pointer] = true
+	}
+	sv := &schemaValidator{model: &apiModel{root: meta}, maxDepth: maxDocumentDepth}
+	sv.validate(meta, root, "", 0)
+	for _, e := range sv.errors {
+		if !reported[e.pointer] {
+			v.report(e.pointer, "%s", e.message)
+		}
+	}
+}
+
+// withoutSwagPlaceholders drops the host, basePath and schemes of root that swag leaves empty when unset
+func withoutSwagPlaceholders(root map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(root))
+	for k, val := range root {
+		if (k == "host" || k == "basePath") && val == "" || k == "schemes" && val == nil {
+			continue
+		}
+		out[k] = val
+	}
+	return out
+}
+
+func (v *docValidator) pathItem(p string, raw interface{}) {
+	pointer := "/paths/" + escapePointer(p)
+	if !strings.HasPrefix(p, "/") {
+		v.report(pointer, "path must begin with a slash")
+	}
+	item, ok := raw.(map[string]interface{})
+	if !ok {
+		v.report(pointer, "path item is not an object")
+		return
+	}
+	if _, ok := item["$ref"]; ok {
+		return
+	}
+
+	shared := v.parameters(pointer+"/parameters", item["parameters"])
+	for _, method := range operationMethods {
+		raw, ok := item[method]
+		if !ok {
+			continue
+		}
+		opPointer := pointer + "/" + method
+		op, ok := raw.(map[string]interface{})
+		if !ok {
+			v.report(opPointer, "operation is not an object")
+			continue
+		}
+
+		declared := v.parameters(opPointer+"/parameters", op["parameters"])
+		for _, match := range pathParamRe.FindAllStringSubmatch(p, -1) {
+			if !declared[match[1]] && !shared[match[1]] {
+				v.report(opPointer, "path parameter %q is not declared", match[1])
+			}
+		}
+
+		if id, ok := op["operationId"].(string); ok {
+			if previous, ok := v.operationIDs[id]; ok {
+				v.report(opPointer+"/operationId", "operationId %q is already used by %s", id, previous)
+			} else {
+				v.operationIDs[id] = opPointer
+			}
+		}
+
+		v.responses(opPointer+"/responses", op["responses"])
+	}
+}
+
+// parameters validates a list of parameters, it returns the names of the path parameters
+func (v *docValidator) parameters(pointer string, raw interface{}) map[string]bool {
+	pathParams := make(map[string]bool)
+	if raw == nil {
+		return pathParams
+	}
+	params, ok := raw.([]interface{})
+	if !ok {
+		v.report(pointer, "parameters is not an array")
+		return pathParams
+	}
+
+	locations := []string{"query", "header", "path", "cookie"}
+	if v.swagger2 {
+		locations = []string{"query", "header", "path", "formData", "body"}
+	}
+
+	for i, raw := range params {
+		paramPointer := fmt.Sprintf("%s/%d", pointer, i)
+		param, ok := raw.(map[string]interface{})
+		if !ok {
+			v.report(paramPointer, "parameter is not an object")
+			continue
+		}
+		if ref, ok := param["$ref"].(string); ok {
+			// Unresolved references are reported by refs
+			if target, ok := resolvePointer(v.root, ref).(map[string]interface{}); ok {
+				param = target
+			} else {
+				continue
+			}
+		}
+
+		name, ok := param["name"].(string)
+		if !ok {
+			v.report(paramPointer, "missing parameter name")
+		}
+		in, _ := param["in"].(string)
+		if !containsString(locations, in) {
+			v.report(paramPointer+"/in", "invalid parameter location %q", in)
+			continue
+		}
+		if in == "path" {
+			pathParams[name] = true
+			if required, _ := param["required"].(bool); !required {
+				v.report(paramPointer+"/required", "path parameter %q must be required", name)
+			}
+		}
+
+		_, hasSchema := param["schema"]
+		switch {
+		case v.swagger2 && in == "body" && !hasSchema:
+			v.report(paramPointer, "body parameter %q has no schema", name)
+		case v.swagger2 && in != "body" && param["type"] == nil:
+			v.report(paramPointer, "parameter %q has no type", name)
+		case !v.swagger2 && !hasSchema && param["content"] == nil:
+			v.report(paramPointer, "parameter %q has neither schema nor content", name)
+		}
+	}
+	return pathParams
+}
+
+func (v *docValidator) responses(pointer string, raw interface{}) {
+	responses, ok := raw.(map[string]interface{})
+	if !ok || len(responses) == 0 {
+		v.report(pointer, "operation has no responses")
+		return
+	}
+	for code, raw := range responses {
+		responsePointer := pointer + "/" + escapePointer(code)
+		if !responseCodeRe.MatchString(code) && !strings.HasPrefix(code, "x-") {
+			v.report(responsePointer, "invalid response code %q", code)
+		}
+		response, ok := raw.(map[string]interface{})
+		if !ok {
+			v.report(responsePointer, "response is not an object")
+			continue
+		}
+		if _, ok := response["$ref"]; ok {
+			continue
+		}
+		if _, ok := response["description"].(string); !ok {
+			v.report(responsePointer, "response has no description")
+		}
+	}
+}
+
+// refs reports the local references of node which do not resolve
+func (v *docValidator) refs(node interface{}, pointer string) {
+	switch t := node.(type) {
+	case map[string]interface{}:
+		for k, child := range t {
+			childPointer := pointer + "/" + escapePointer(k)
+			if ref, ok := child.(string); ok && k == "$ref" {
+				// References to other documents are not followed
+				if strings.HasPrefix(ref, "#") && resolvePointer(v.root, ref) == nil {
+					v.report(childPointer, "unresolved reference %q", ref)
+				}
+				continue
+			}
+			v.refs(child, childPointer)
+		}
+	case []interface{}:
+		for i, child := range t {
+			v.refs(child, fmt.Sprintf("%s/%d", pointer, i))
+		}
+	}
+}
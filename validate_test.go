@@ -0,0 +1,144 @@
+package swagger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/swaggo/swag"
+)
+
+func Test_Validate(t *testing.T) {
+	registrationOnce.Do(func() {
+		swag.Register(swag.Name, &mockedSwag{})
+	})
+
+	tests := []struct {
+		name     string
+		config   Config
+		findings []Finding
+	}{
+		{
+			name:   "Registered instance",
+			config: Config{},
+		},
+		{
+			name:     "Missing instance",
+			config:   Config{InstanceName: "missing"},
+			findings: []Finding{{Instance: "missing", Message: `cannot read document: no swag named "missing" was registered`}},
+		},
+		{
+			name:     "Malformed document",
+			config:   Config{Spec: BytesSpec([]byte(`{"swagger":"2.0",`))},
+			findings: []Finding{{Message: "invalid json: unexpected EOF"}},
+		},
+		{
+			name:     "Unknown format",
+			config:   Config{Spec: BytesSpec([]byte(`{"info":{"title":"API","version":"1"}}`))},
+			findings: []Finding{{Message: "missing swagger or openapi version"}},
+		},
+		{
+			name: "Swagger 2.0 structure",
+			config: Config{Spec: BytesSpec([]byte(`{"swagger":"2.0","info":{"title":"API"},"paths":{"/users/{id}":{
+				"get":{"operationId":"getUser","parameters":[{"name":"q","in":"query"}],"responses":{"200":{"schema":{"$ref":"#/definitions/User"}}}},
+				"put":{"operationId":"getUser","parameters":[{"name":"id","in":"path","required":true,"type":"string"},{"name":"body","in":"body"}],"responses":{}}}}}`))},
+			findings: []Finding{
+				{Pointer: "/info/version", Message: "missing version"},
+				{Pointer: "/paths/~1users~1{id}/get/parameters/0", Message: `parameter "q" has no type`},
+				{Pointer: "/paths/~1users~1{id}/get", Message: `path parameter "id" is not declared`},
+				{Pointer: "/paths/~1users~1{id}/get/responses/200", Message: "response has no description"},
+				{Pointer: "/paths/~1users~1{id}/put/parameters/1", Message: `body parameter "body" has no schema`},
+				{Pointer: "/paths/~1users~1{id}/put/operationId", Message: `operationId "getUser" is already used by /paths/~1users~1{id}/get`},
+				{Pointer: "/paths/~1users~1{id}/put/responses", Message: "operation has no responses"},
+				{Pointer: "/paths/~1users~1{id}/get/responses/200/schema/$ref", Message: `unresolved reference "#/definitions/User"`},
+			},
+		},
+		{
+			name: "OpenAPI 3 document",
+			config: Config{Spec: BytesSpec([]byte("openapi: 3.0.3\ninfo:\n  title: API\n  version: \"1\"\npaths:\n  /a:\n    get:\n" +
+				"      parameters:\n        - $ref: '#/components/parameters/Limit'\n      responses:\n        \"200\":\n          description: ok\n" +
+				"components:\n  parameters:\n    Limit:\n      name: limit\n      in: query\n      schema:\n        type: integer\n"))},
+		},
+		{
+			name: "Swagger 2.0 schema",
+			config: Config{Spec: BytesSpec([]byte(`{"swagger":"2.0","info":{"title":"API","version":"1"},"host":"","basePath":"","schemes":null,
+				"bogus":true,"x-logo":"logo.png","paths":{"/a":{"get":{"responses":{"200":{"description":"ok","schema":{"type":"strin"}}}}}}}`))},
+			findings: []Finding{
+				{Pointer: "/bogus", Message: "property is not allowed"},
+				{Pointer: "/paths/~1a/get/responses/200", Message: "value does not match any of the schemas"},
+			},
+		},
+		{
+			name: "OpenAPI 3.0 schema",
+			config: Config{Spec: BytesSpec([]byte(`{"openapi":"3.0.3","info":{"title":"API","version":"1","summary":"API"},"paths":{},
+				"components":{"schemas":{"Age":{"type":"integer","exclusiveMinimum":0},"Name":{"type":"string","nullable":true}}}}`))},
+			findings: []Finding{
+				{Pointer: "/components/schemas/Age", Message: "value does not match any of the schemas"},
+				{Pointer: "/info/summary", Message: "property is not allowed"},
+			},
+		},
+		{
+			name: "Instances of the selector",
+			config: Config{URLs: []SpecURL{
+				{Name: "Petstore", URL: "https://petstore.swagger.io/v2/swagger.json"},
+				{Name: "Admin", InstanceName: "admin", Spec: BytesSpec([]byte(`{"openapi":"3.2.0","info":{"title":"Admin","version":"1"},"paths":{}}`))},
+			}},
+			findings: []Finding{{Instance: "admin", Pointer: "/openapi", Message: "unsupported version 3.2.0, expected 3.0.x or 3.1.x"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.config)
+			if tt.findings == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected a *ValidationError, got %v", err)
+			}
+			if !reflect.DeepEqual(verr.Findings, tt.findings) {
+				t.Fatalf("Findings: got %v - expected %v", verr.Findings, tt.findings)
+			}
+		})
+	}
+}
+
+func Test_NewE(t *testing.T) {
+	if _, err := NewE(Config{Spec: BytesSpec([]byte(`{}`))}); err == nil {
+		t.Fatal("expected a validation error")
+	}
+	if _, err := NewE(Config{OutputVersion: "2.0"}); err == nil {
+		t.Fatal("expected a configuration error")
+	}
+
+	handler, err := NewE(Config{Spec: BytesSpec([]byte(`{"swagger":"2.0","info":{"title":"API","version":"1"},"paths":{}}`))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if handler == nil {
+		t.Fatal("expected a handler")
+	}
+
+	t.Run("Should not start hot reload for an invalid document", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "swagger.json")
+		if err := os.WriteFile(file, []byte(`{}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		waitWatchers(t, 0)
+		if _, err := NewE(Config{HotReload: &HotReloadConfig{File: file, Interval: time.Millisecond}}); err == nil {
+			t.Fatal("expected a validation error")
+		}
+		if n := runningWatchers(); n != 0 {
+			t.Fatalf(`Watchers: got %v - expected %v`, n, 0)
+		}
+	})
+}
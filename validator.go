@@ -0,0 +1,523 @@
+package swagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// ValidatorConfig configures the middleware validating requests against the document.
+type ValidatorConfig struct {
+	// Next defines a function to skip this middleware when returned true.
+	// Operations can also opt out with the "x-skip-validation: true" extension.
+	// default: nil
+	Next func(c *fiber.Ctx) bool
+
+	// Name of the swag instance holding the document.
+	// default: ""
+	InstanceName string
+
+	// Source of the document, takes precedence over InstanceName.
+	// The document is read once, on the first request.
+	// default: SwagSpec(InstanceName)
+	Spec Spec
+
+	// Validates the responses of the handlers against the documented responses of the operation:
+	// the status code, the required headers, the content type and the JSON body.
+	// default: ResponseValidationOff
+	Responses ResponseValidation
+
+	// Function answering invalid requests, called with a *RequestValidationError, and invalid responses
+	// in the ResponseValidationFail mode, called with a *ResponseValidationError.
+	// default: a 400 Bad Request, respectively 500 Internal Server Error, application/problem+json response listing the errors
+	ErrorHandler fiber.ErrorHandler
+}
+
+// ResponseValidation tells what happens to responses which do not match the document.
+type ResponseValidation int
+
+const (
+	// ResponseValidationOff does not check responses.
+	ResponseValidationOff ResponseValidation = iota
+	// ResponseValidationLog logs the errors as warnings.
+	ResponseValidationLog
+	// ResponseValidationHeader lists the errors in the X-Response-Validation header of the response.
+	ResponseValidationHeader
+	// ResponseValidationFail replaces the response with the one of ErrorHandler.
+	ResponseValidationFail
+)
+
+// HeaderResponseValidation is the header listing the errors in the ResponseValidationHeader mode
+const HeaderResponseValidation = "X-Response-Validation"
+
+// FieldError is a part of a request or a response which does not match the document.
+type FieldError struct {
+	// Location of the value: "path", "query", "header", "cookie" or "body".
+	In string `json:"in"`
+
+	// Name of the parameter or the header.
+	Name string `json:"name,omitempty"`
+
+	// JSON pointer to the offending value within the parameter or the body.
+	Pointer string `json:"pointer,omitempty"`
+
+	Message string `json:"message"`
+}
+
+func (fe FieldError) String() string {
+	var sb strings.Builder
+	sb.WriteString(fe.In)
+	if fe.Name != "" {
+		sb.WriteString(" " + fe.Name)
+	}
+	if fe.Pointer != "" {
+		sb.WriteString(" " + fe.Pointer)
+	}
+	sb.WriteString(": " + fe.Message)
+	return sb.String()
+}
+
+// RequestValidationError is the error of a request which does not match its operation.
+type RequestValidationError struct {
+	// Method and path template of the operation.
+	Method string
+	Path   string
+
+	Errors []FieldError
+}
+
+func (e *RequestValidationError) Error() string {
+	return fmt.Sprintf("request does not match %s %s: %s", e.Method, e.Path, joinFieldErrors(e.Errors))
+}
+
+// ResponseValidationError is the error of a response which does not match its operation.
+type ResponseValidationError struct {
+	// Method and path template of the operation.
+	Method string
+	Path   string
+
+	StatusCode int
+	Errors     []FieldError
+}
+
+func (e *ResponseValidationError) Error() string {
+	return fmt.Sprintf("response %d does not match %s %s: %s", e.StatusCode, e.Method, e.Path, joinFieldErrors(e.Errors))
+}
+
+// problem is an RFC 9457 problem details object
+type problem struct {
+	Type   string       `json:"type"`
+	Title  string       `json:"title"`
+	Status int          `json:"status"`
+	Detail string       `json:"detail,omitempty"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+const mimeProblemJSON = "application/problem+json"
+
+// Validator returns a middleware validating the parameters, the content type and the JSON body of requests
+// against the operation of the document matching their method and path. Requests without an operation
+// in the document are passed on unchecked.
+func Validator(config ...ValidatorConfig) fiber.Handler {
+	var cfg ValidatorConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Spec == nil {
+		cfg.Spec = SwagSpec(cfg.InstanceName)
+	}
+	if cfg.ErrorHandler == nil {
+		cfg.ErrorHandler = writeValidationProblem
+	}
+
+	models := &modelCache{spec: cfg.Spec}
+
+	return func(c *fiber.Ctx) error {
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		m, err := models.get(c.UserContext())
+		if err != nil {
+			return err
+		}
+
+		op, values := m.match(c.Method(), c.Path())
+		if op == nil || skipValidation(op) {
+			return c.Next()
+		}
+
+		if errs := validateRequest(c, m, op, values); len(errs) > 0 {
+			return cfg.ErrorHandler(c, &RequestValidationError{Method: op.method, Path: op.path, Errors: errs})
+		}
+		if cfg.Responses == ResponseValidationOff {
+			return c.Next()
+		}
+
+		// Errors are turned into responses by the error handler of the app, after this middleware
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		errs := validateResponse(c, m, op)
+		if len(errs) == 0 {
+			return nil
+		}
+		verr := &ResponseValidationError{Method: op.method, Path: op.path, StatusCode: c.Response().StatusCode(), Errors: errs}
+		switch cfg.Responses {
+		case ResponseValidationLog:
+			log.Warnw("swagger: response does not match the API definition",
+				"method", verr.Method, "path", verr.Path, "status", verr.StatusCode, "errors", joinFieldErrors(errs))
+		case ResponseValidationHeader:
+			c.Set(HeaderResponseValidation, joinFieldErrors(errs))
+		case ResponseValidationFail:
+			c.Response().ResetBody()
+			c.Response().Header.Del(fiber.HeaderContentEncoding)
+			return cfg.ErrorHandler(c, verr)
+		}
+		return nil
+	}
+}
+
+// writeValidationProblem answers an invalid request or response with a problem response
+func writeValidationProblem(c *fiber.Ctx, err error) error {
+	p := problem{Type: "about:blank"}
+	switch verr := err.(type) {
+	case *RequestValidationError:
+		p.Status, p.Detail, p.Errors = fiber.StatusBadRequest, "The request does not match the API definition.", verr.Errors
+	case *ResponseValidationError:
+		p.Status, p.Detail, p.Errors = fiber.StatusInternalServerError, "The response does not match the API definition.", verr.Errors
+	default:
+		return err
+	}
+	p.Title = utils.StatusMessage(p.Status)
+	return c.Status(p.Status).JSON(p, mimeProblemJSON)
+}
+
+func skipValidation(op *apiOperation) bool {
+	skip, _ := op.op["x-skip-validation"].(bool)
+	return skip
+}
+
+// validateRequest checks the parameters and the body of a request
+func validateRequest(c *fiber.Ctx, m *apiModel, op *apiOperation, values map[string]string) []FieldError {
+	var errs []FieldError
+
+	for _, param := range op.params {
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+
+		raw, present := paramValues(c, in, name, values)
+		if !present {
+			if required, _ := param["required"].(bool); required {
+				errs = append(errs, FieldError{In: in, Name: name, Message: "parameter is required"})
+			}
+			continue
+		}
+
+		schema := m.deref(param["schema"])
+		if schema == nil || schemaType(schema) == "object" {
+			// Parameters described by content or objects are not checked
+			continue
+		}
+		value := coerceParam(m, schema, raw, param)
+
+		sv := &schemaValidator{model: m, direction: toServer}
+		sv.validate(schema, value, "", 0)
+		for _, e := range sv.errors {
+			errs = append(errs, FieldError{In: in, Name: name, Pointer: e.pointer, Message: e.message})
+		}
+	}
+
+	return append(errs, validateRequestBody(c, m, op)...)
+}
+
+// paramValues returns the raw values of a parameter and whether it is present in the request
+func paramValues(c *fiber.Ctx, in, name string, values map[string]string) ([]string, bool) {
+	switch in {
+	case "path":
+		v, ok := values[name]
+		if unescaped, err := url.PathUnescape(v); err == nil {
+			v = unescaped
+		}
+		return []string{v}, ok
+	case "query":
+		var raw []string
+		for _, v := range c.Context().QueryArgs().PeekMulti(name) {
+			raw = append(raw, string(v))
+		}
+		return raw, len(raw) > 0
+	case "header":
+		v := c.Request().Header.Peek(name)
+		return []string{string(v)}, v != nil
+	case "cookie":
+		v := c.Request().Header.Cookie(name)
+		return []string{string(v)}, v != nil
+	}
+	return nil, false
+}
+
+func validateRequestBody(c *fiber.Ctx, m *apiModel, op *apiOperation) []FieldError {
+	rb := op.requestBody
+	if rb == nil {
+		return nil
+	}
+
+	body := c.Body()
+	if len(body) == 0 {
+		if required, _ := rb["required"].(bool); required {
+			return []FieldError{{In: "body", Message: "request body is required"}}
+		}
+		return nil
+	}
+
+	content, _ := rb["content"].(map[string]interface{})
+	if len(content) == 0 {
+		return nil
+	}
+	mime := mediaType(c.Get(fiber.HeaderContentType))
+	media, ok := matchMediaType(content, mime)
+	if !ok {
+		return []FieldError{{
+			In:      "header",
+			Name:    fiber.HeaderContentType,
+			Message: fmt.Sprintf("content type %q is not one of %s", mime, strings.Join(sortedKeys(content), ", ")),
+		}}
+	}
+
+	schema, hasSchema := media["schema"]
+	if !hasSchema || !isJSONMediaType(mime) {
+		return nil
+	}
+
+	value, err := decodeJSON(body)
+	if err != nil {
+		return []FieldError{{In: "body", Message: "invalid json: " + err.Error()}}
+	}
+
+	sv := &schemaValidator{model: m, direction: toServer}
+	sv.validate(schema, value, "", 0)
+	errs := make([]FieldError, 0, len(sv.errors))
+	for _, e := range sv.errors {
+		errs = append(errs, FieldError{In: "body", Pointer: e.pointer, Message: e.message})
+	}
+	return errs
+}
+
+// validateResponse checks the status code, the headers and the body of the response to a request
+func validateResponse(c *fiber.Ctx, m *apiModel, op *apiOperation) []FieldError {
+	res := c.Response()
+	status := res.StatusCode()
+
+	code := strconv.Itoa(status)
+	raw, ok := op.responses[code]
+	if !ok {
+		raw, ok = op.responses[code[:1]+"XX"]
+	}
+	if !ok {
+		raw, ok = op.responses["default"]
+	}
+	if !ok {
+		return []FieldError{{In: "status", Message: fmt.Sprintf("status %d is not documented", status)}}
+	}
+	response := m.deref(raw)
+	if response == nil {
+		return nil
+	}
+
+	var errs []FieldError
+	headers, _ := response["headers"].(map[string]interface{})
+	for _, name := range sortedKeys(headers) {
+		// The Content-Type header is described by content
+		if strings.EqualFold(name, fiber.HeaderContentType) {
+			continue
+		}
+		header := m.deref(headers[name])
+		if header == nil {
+			continue
+		}
+		v := res.Header.Peek(name)
+		if v == nil {
+			if required, _ := header["required"].(bool); required {
+				errs = append(errs, FieldError{In: "header", Name: name, Message: "header is required"})
+			}
+			continue
+		}
+		schema := m.deref(header["schema"])
+		if schema == nil || schemaType(schema) == "object" {
+			continue
+		}
+		sv := &schemaValidator{model: m, direction: toClient}
+		sv.validate(schema, coerceParam(m, schema, []string{string(v)}, header), "", 0)
+		for _, e := range sv.errors {
+			errs = append(errs, FieldError{In: "header", Name: name, Pointer: e.pointer, Message: e.message})
+		}
+	}
+
+	content, _ := response["content"].(map[string]interface{})
+	// Streamed and compressed bodies are not read, HEAD responses have none
+	if len(content) == 0 || c.Method() == fiber.MethodHead || res.IsBodyStream() || len(res.Header.Peek(fiber.HeaderContentEncoding)) > 0 {
+		return errs
+	}
+
+	body := res.Body()
+	if len(body) == 0 {
+		return append(errs, FieldError{In: "body", Message: "response body is missing"})
+	}
+	mime := mediaType(string(res.Header.ContentType()))
+	media, ok := matchMediaType(content, mime)
+	if !ok {
+		return append(errs, FieldError{
+			In:      "header",
+			Name:    fiber.HeaderContentType,
+			Message: fmt.Sprintf("content type %q is not one of %s", mime, strings.Join(sortedKeys(content), ", ")),
+		})
+	}
+
+	schema, hasSchema := media["schema"]
+	if !hasSchema || !isJSONMediaType(mime) {
+		return errs
+	}
+	value, err := decodeJSON(body)
+	if err != nil {
+		return append(errs, FieldError{In: "body", Message: "invalid json: " + err.Error()})
+	}
+	sv := &schemaValidator{model: m, direction: toClient}
+	sv.validate(schema, value, "", 0)
+	for _, e := range sv.errors {
+		errs = append(errs, FieldError{In: "body", Pointer: e.pointer, Message: e.message})
+	}
+	return errs
+}
+
+// coerceParam converts the raw values of a parameter to the JSON value described by its schema,
+// values which cannot be converted are left as strings for the validation to report them
+func coerceParam(m *apiModel, schema map[string]interface{}, raw []string, param map[string]interface{}) interface{} {
+	if schemaType(schema) != "array" {
+		return coerceScalar(schema, raw[0])
+	}
+
+	items := m.deref(schema["items"])
+	parts := raw
+	if len(raw) == 1 {
+		separator := ","
+		switch param["style"] {
+		case "spaceDelimited":
+			separator = " "
+		case "pipeDelimited":
+			separator = "|"
+		}
+		parts = strings.Split(raw[0], separator)
+		if raw[0] == "" {
+			parts = nil
+		}
+	}
+
+	values := make([]interface{}, 0, len(parts))
+	for _, part := range parts {
+		values = append(values, coerceScalar(items, part))
+	}
+	return values
+}
+
+// Numbers of parameters follow the JSON syntax, unlike strconv.ParseFloat which also accepts "NaN", "Inf" or "1_0"
+var jsonNumberRe = regexp.MustCompile(`^-?(0|[1-9]\d*)(\.\d+)?([eE][+-]?\d+)?$`)
+
+func coerceScalar(schema map[string]interface{}, raw string) interface{} {
+	switch schemaType(schema) {
+	case "integer", "number":
+		if jsonNumberRe.MatchString(raw) {
+			return json.Number(raw)
+		}
+	case "boolean":
+		if raw == "true" || raw == "false" {
+			return raw == "true"
+		}
+	}
+	return raw
+}
+
+// schemaType returns the type of a schema other than null
+func schemaType(schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, s := range stringSlice(t) {
+			if s != "null" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// mediaType returns the media type of a Content-Type header without its parameters
+func mediaType(contentType string) string {
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+// matchMediaType returns the media type object of content matching mime, ranges such as "image/*" included
+func matchMediaType(content map[string]interface{}, mime string) (map[string]interface{}, bool) {
+	candidates := []string{mime}
+	if i := strings.IndexByte(mime, '/'); i > 0 {
+		candidates = append(candidates, mime[:i]+"/*")
+	}
+	candidates = append(candidates, "*/*")
+
+	for _, candidate := range candidates {
+		for key, media := range content {
+			if mediaType(key) == candidate {
+				m, _ := media.(map[string]interface{})
+				return m, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func isJSONMediaType(mime string) bool {
+	return mime == fiber.MIMEApplicationJSON || strings.HasSuffix(mime, "+json")
+}
+
+func decodeJSON(body []byte) (interface{}, error) {
+	var value interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after the value")
+	}
+	return value, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinFieldErrors(errs []FieldError) string {
+	msgs := make([]string, 0, len(errs))
+	for _, e := range errs {
+		msgs = append(msgs, e.String())
+	}
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,390 @@
+package swagger
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const validatorSwaggerDoc = `{
+	"swagger": "2.0",
+	"basePath": "/api",
+	"consumes": ["application/json"],
+	"paths": {
+		"/users": {
+			"get": {
+				"parameters": [
+					{"name": "limit", "in": "query", "type": "integer", "minimum": 1, "maximum": 100},
+					{"name": "price", "in": "query", "type": "number", "minimum": 0, "maximum": 10},
+					{"name": "ids", "in": "query", "type": "array", "items": {"type": "integer"}},
+					{"name": "X-Tenant", "in": "header", "type": "string", "required": true}
+				],
+				"responses": {"200": {"description": "ok"}}
+			},
+			"post": {
+				"parameters": [{"name": "user", "in": "body", "required": true, "schema": {"$ref": "#/definitions/User"}}],
+				"responses": {"201": {"description": "created"}}
+			}
+		},
+		"/users/me": {
+			"get": {"responses": {"200": {"description": "ok"}}}
+		},
+		"/users/{id}": {
+			"parameters": [{"name": "id", "in": "path", "required": true, "type": "string", "format": "uuid"}],
+			"get": {"responses": {"200": {"description": "ok"}}},
+			"delete": {"x-skip-validation": true, "responses": {"204": {"description": "deleted"}}}
+		}
+	},
+	"definitions": {
+		"User": {
+			"type": "object",
+			"required": ["id", "name", "email"],
+			"properties": {
+				"id": {"type": "integer", "readOnly": true},
+				"name": {"type": "string", "minLength": 1},
+				"email": {"type": "string", "format": "email"},
+				"role": {"type": "string", "enum": ["admin", "member"]},
+				"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true}
+			}
+		}
+	}
+}`
+
+func Test_Validator(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		url         string
+		headers     map[string]string
+		body        string
+		statusCode  int
+		fieldErrors []FieldError
+	}{
+		{
+			name:       "Valid query",
+			method:     http.MethodGet,
+			url:        "/api/users?limit=10&ids=1,2&price=9.5",
+			headers:    map[string]string{"X-Tenant": "acme"},
+			statusCode: 200,
+		},
+		{
+			name:       "Invalid query and missing header",
+			method:     http.MethodGet,
+			url:        "/api/users?limit=0&ids=1,a",
+			statusCode: 400,
+			fieldErrors: []FieldError{
+				{In: "query", Name: "limit", Message: "value must be at least 1"},
+				{In: "query", Name: "ids", Pointer: "/1", Message: "expected integer, got string"},
+				{In: "header", Name: "X-Tenant", Message: "parameter is required"},
+			},
+		},
+		{
+			name:        "Non-JSON number NaN",
+			method:      http.MethodGet,
+			url:         "/api/users?price=NaN",
+			headers:     map[string]string{"X-Tenant": "acme"},
+			statusCode:  400,
+			fieldErrors: []FieldError{{In: "query", Name: "price", Message: "expected number, got string"}},
+		},
+		{
+			name:        "Non-JSON number Inf",
+			method:      http.MethodGet,
+			url:         "/api/users?price=Inf",
+			headers:     map[string]string{"X-Tenant": "acme"},
+			statusCode:  400,
+			fieldErrors: []FieldError{{In: "query", Name: "price", Message: "expected number, got string"}},
+		},
+		{
+			name:        "Non-JSON number 1_0",
+			method:      http.MethodGet,
+			url:         "/api/users?price=1_0",
+			headers:     map[string]string{"X-Tenant": "acme"},
+			statusCode:  400,
+			fieldErrors: []FieldError{{In: "query", Name: "price", Message: "expected number, got string"}},
+		},
+		{
+			name:       "Literal path before template",
+			method:     http.MethodGet,
+			url:        "/api/users/me",
+			statusCode: 200,
+		},
+		{
+			name:        "Invalid path parameter",
+			method:      http.MethodGet,
+			url:         "/api/users/42",
+			statusCode:  400,
+			fieldErrors: []FieldError{{In: "path", Name: "id", Message: "string is not a valid uuid"}},
+		},
+		{
+			name:       "Operation opting out",
+			method:     http.MethodDelete,
+			url:        "/api/users/42",
+			statusCode: 200,
+		},
+		{
+			name:       "Valid body without read-only property",
+			method:     http.MethodPost,
+			url:        "/api/users",
+			headers:    map[string]string{"Content-Type": "application/json; charset=utf-8"},
+			body:       `{"name":"Ada","email":"ada@example.com","role":"admin"}`,
+			statusCode: 200,
+		},
+		{
+			name:       "Invalid body",
+			method:     http.MethodPost,
+			url:        "/api/users",
+			headers:    map[string]string{"Content-Type": "application/json"},
+			body:       `{"name":"","role":"owner","tags":["a","a"]}`,
+			statusCode: 400,
+			fieldErrors: []FieldError{
+				{In: "body", Pointer: "/email", Message: "property is required"},
+				{In: "body", Pointer: "/name", Message: "string is shorter than 1 characters"},
+				{In: "body", Pointer: "/role", Message: `value is not one of ["admin","member"]`},
+				{In: "body", Pointer: "/tags", Message: "array items 0 and 1 are equal"},
+			},
+		},
+		{
+			name:        "Malformed body",
+			method:      http.MethodPost,
+			url:         "/api/users",
+			headers:     map[string]string{"Content-Type": "application/json"},
+			body:        `{"name":`,
+			statusCode:  400,
+			fieldErrors: []FieldError{{In: "body", Message: "invalid json: unexpected EOF"}},
+		},
+		{
+			name:        "Missing body",
+			method:      http.MethodPost,
+			url:         "/api/users",
+			statusCode:  400,
+			fieldErrors: []FieldError{{In: "body", Message: "request body is required"}},
+		},
+		{
+			name:        "Unsupported content type",
+			method:      http.MethodPost,
+			url:         "/api/users",
+			headers:     map[string]string{"Content-Type": "text/plain"},
+			body:        "Ada",
+			statusCode:  400,
+			fieldErrors: []FieldError{{In: "header", Name: "Content-Type", Message: `content type "text/plain" is not one of application/json`}},
+		},
+		{
+			name:       "Undocumented route",
+			method:     http.MethodGet,
+			url:        "/health",
+			statusCode: 200,
+		},
+	}
+
+	app := fiber.New()
+	app.Use(Validator(ValidatorConfig{Spec: BytesSpec([]byte(validatorSwaggerDoc))}))
+	app.All("/*", func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tt.statusCode {
+				body, _ := io.ReadAll(resp.Body)
+				t.Fatalf(`StatusCode: got %v - expected %v: %s`, resp.StatusCode, tt.statusCode, body)
+			}
+			if tt.fieldErrors == nil {
+				return
+			}
+
+			if ct := resp.Header.Get("Content-Type"); ct != "application/problem+json" {
+				t.Fatalf(`Content-Type: got %s - expected %s`, ct, "application/problem+json")
+			}
+			var p problem
+			if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+				t.Fatal(err)
+			}
+			if p.Status != 400 || p.Title != "Bad Request" {
+				t.Fatalf("unexpected problem %+v", p)
+			}
+			if !reflect.DeepEqual(p.Errors, tt.fieldErrors) {
+				t.Fatalf("Errors: got %+v - expected %+v", p.Errors, tt.fieldErrors)
+			}
+		})
+	}
+}
+
+func Test_Validator_Next_And_ErrorHandler(t *testing.T) {
+	app := fiber.New()
+	app.Use(Validator(ValidatorConfig{
+		Spec: BytesSpec([]byte(validatorSwaggerDoc)),
+		Next: func(c *fiber.Ctx) bool {
+			return c.Get("X-Skip") != ""
+		},
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return c.Status(422).SendString(err.Error())
+		},
+	}))
+	app.Get("/api/users/:id", func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+
+	tests := []struct {
+		skip       string
+		statusCode int
+		body       string
+	}{
+		{statusCode: 422, body: "request does not match GET /api/users/{id}: path id: string is not a valid uuid"},
+		{skip: "1", statusCode: 200, body: "OK"},
+	}
+
+	for _, tt := range tests {
+		req, err := http.NewRequest(http.MethodGet, "/api/users/42", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("X-Skip", tt.skip)
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tt.statusCode {
+			t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, tt.statusCode)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != tt.body {
+			t.Fatalf("Body: got %s - expected %s", body, tt.body)
+		}
+	}
+}
+
+func Test_Validator_Responses(t *testing.T) {
+	doc := `{
+		"openapi": "3.0.3",
+		"paths": {
+			"/items/{id}": {
+				"get": {
+					"responses": {
+						"200": {
+							"description": "ok",
+							"headers": {"X-Version": {"required": true, "schema": {"type": "integer"}}},
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Item"}}}
+						},
+						"4XX": {"description": "error", "content": {"application/problem+json": {"schema": {"type": "object"}}}}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Item": {
+					"type": "object",
+					"required": ["id", "password"],
+					"properties": {"id": {"type": "integer"}, "password": {"type": "string", "writeOnly": true}}
+				}
+			}
+		}
+	}`
+
+	handler := func(c *fiber.Ctx) error {
+		switch c.Params("id") {
+		case "1":
+			c.Set("X-Version", "2")
+			return c.JSON(fiber.Map{"id": 1})
+		case "2":
+			c.Set("X-Version", "two")
+			return c.JSON(fiber.Map{"id": "2"})
+		case "3":
+			return c.Status(404).JSON(fiber.Map{}, "application/problem+json")
+		case "4":
+			return c.Status(500).SendString("boom")
+		}
+		return c.SendString("plain")
+	}
+
+	tests := []struct {
+		name       string
+		mode       ResponseValidation
+		id         string
+		statusCode int
+		header     string
+		body       string
+	}{
+		{name: "Valid response", mode: ResponseValidationFail, id: "1", statusCode: 200, body: `{"id":1}`},
+		{name: "Status range", mode: ResponseValidationFail, id: "3", statusCode: 404, body: `{}`},
+		{
+			name:       "Header mode",
+			mode:       ResponseValidationHeader,
+			id:         "2",
+			statusCode: 200,
+			header:     "header X-Version: expected integer, got string; body /id: expected integer, got string",
+			body:       `{"id":"2"}`,
+		},
+		{name: "Log mode", mode: ResponseValidationLog, id: "2", statusCode: 200, body: `{"id":"2"}`},
+		{
+			name:       "Fail mode",
+			mode:       ResponseValidationFail,
+			id:         "4",
+			statusCode: 500,
+			body: `{"type":"about:blank","title":"Internal Server Error","status":500,` +
+				`"detail":"The response does not match the API definition.","errors":[{"in":"status","message":"status 500 is not documented"}]}`,
+		},
+		{
+			name:       "Content type",
+			mode:       ResponseValidationHeader,
+			id:         "5",
+			statusCode: 200,
+			header:     `header X-Version: header is required; header Content-Type: content type "text/plain" is not one of application/json`,
+			body:       "plain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(Validator(ValidatorConfig{Spec: BytesSpec([]byte(doc)), Responses: tt.mode}))
+			app.Get("/items/:id", handler)
+
+			req, err := http.NewRequest(http.MethodGet, "/items/"+tt.id, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tt.statusCode {
+				t.Fatalf(`StatusCode: got %v - expected %v`, resp.StatusCode, tt.statusCode)
+			}
+			if header := resp.Header.Get(HeaderResponseValidation); header != tt.header {
+				t.Fatalf(`%s: got %s - expected %s`, HeaderResponseValidation, header, tt.header)
+			}
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(body) != tt.body {
+				t.Fatalf("Body: got %s - expected %s", body, tt.body)
+			}
+		})
+	}
+}
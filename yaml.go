@@ -0,0 +1,148 @@
+package swagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	mimeYAML = "application/yaml"
+)
+
+// Accepted media types for YAML documents, the first one is used in responses
+var yamlMIMEs = []string{mimeYAML, "application/x-yaml", "text/yaml", "text/x-yaml"}
+
+// jsonToYAML converts a JSON document to YAML keeping the key order of the source.
+func jsonToYAML(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	v, err := decodeOrdered(dec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json document: %w", err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("invalid json document: unexpected data after top-level value")
+	}
+
+	return yaml.Marshal(v)
+}
+
+// decodeOrdered reads the next JSON value from dec, objects are returned as
+// yaml.MapSlice so that the YAML encoder preserves the original key order.
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			m := yaml.MapSlice{}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, ok := keyTok.(string)
+				if !ok {
+					return nil, fmt.Errorf("unexpected object key %v", keyTok)
+				}
+				val, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				m = append(m, yaml.MapItem{Key: key, Value: val})
+			}
+			// Consume closing '}'
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return m, nil
+		case '[':
+			s := []interface{}{}
+			for dec.More() {
+				val, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				s = append(s, val)
+			}
+			// Consume closing ']'
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return s, nil
+		}
+		return nil, fmt.Errorf("unexpected delimiter %v", t)
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		return t.Float64()
+	default:
+		return tok, nil
+	}
+}
+
+// yamlToJSON converts a YAML document to JSON keeping the key order of the source.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var v yaml.MapSlice
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("invalid yaml document: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := encodeOrdered(&buf, v); err != nil {
+		return nil, fmt.Errorf("invalid yaml document: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeOrdered writes v as JSON, yaml.MapSlice values are written as objects in their original order.
+func encodeOrdered(buf *bytes.Buffer, v interface{}) error {
+	switch t := v.(type) {
+	case yaml.MapSlice:
+		buf.WriteByte('{')
+		for i, item := range t {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(fmt.Sprint(item.Key))
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := encodeOrdered(buf, item.Value); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range t {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeOrdered(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		out, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		buf.Write(out)
+	}
+	return nil
+}